@@ -0,0 +1,42 @@
+package artist
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// flexID id, который API отдаёт то строкой, то числом.
+type flexID string
+
+func (f *flexID) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*f = flexID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("artist: id must be string or number: %s", b)
+	}
+	*f = flexID(n)
+	return nil
+}
+
+func (a *Artist) UnmarshalJSON(data []byte) error {
+	type plain Artist
+	aux := struct {
+		*plain
+		ID flexID `json:"id"`
+	}{plain: (*plain)(a), ID: flexID(a.ID)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	a.ID = string(aux.ID)
+	return nil
+}
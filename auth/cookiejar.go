@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CookieJar http.CookieJar поверх net/http/cookiejar, который дополнительно запоминает
+// сохранённые cookies, чтобы их можно было сериализовать (Snapshot.Cookies) и восстановить.
+type CookieJar struct {
+	jar *cookiejar.Jar
+
+	mu      sync.Mutex
+	entries map[string]Cookie
+}
+
+// NewCookieJar создаёт пустой jar.
+func NewCookieJar() *CookieJar {
+	jar, _ := cookiejar.New(nil) // ошибка возможна только из опций
+	return &CookieJar{jar: jar, entries: map[string]Cookie{}}
+}
+
+// Cookies реализует http.CookieJar.
+func (j *CookieJar) Cookies(u *url.URL) []*http.Cookie { return j.jar.Cookies(u) }
+
+// SetCookies реализует http.CookieJar и запоминает для сериализации только cookies,
+// принятые внутренним jar (отклонённые из-за домена не сохраняются и не восстанавливаются).
+func (j *CookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.jar.SetCookies(u, cookies)
+	now := time.Now()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, hc := range cookies {
+		ck := Cookie{
+			Domain:   strings.TrimPrefix(strings.ToLower(hc.Domain), "."),
+			Path:     hc.Path,
+			Name:     hc.Name,
+			Value:    hc.Value,
+			Expires:  hc.Expires,
+			Secure:   hc.Secure,
+			HttpOnly: hc.HttpOnly,
+		}
+		ck.IncludeSubdomains = ck.Domain != ""
+		if ck.Domain == "" {
+			ck.Domain = strings.ToLower(u.Hostname())
+		}
+		if ck.Path == "" || ck.Path[0] != '/' {
+			ck.Path = defaultCookiePath(u.Path)
+		}
+		switch {
+		case hc.MaxAge < 0:
+			ck.Expires = now.Add(-time.Second)
+		case hc.MaxAge > 0:
+			ck.Expires = now.Add(time.Duration(hc.MaxAge) * time.Second)
+		}
+		key := ck.Domain + ";" + ck.Path + ";" + ck.Name
+		if ck.expired(now) {
+			delete(j.entries, key)
+			continue
+		}
+		if !j.accepted(ck) {
+			continue
+		}
+		j.entries[key] = ck
+	}
+}
+
+// accepted проверяет, что внутренний jar действительно хранит cookie.
+func (j *CookieJar) accepted(ck Cookie) bool {
+	for _, hc := range j.jar.Cookies(&url.URL{Scheme: "https", Host: ck.Domain, Path: ck.Path}) {
+		if hc.Name == ck.Name && hc.Value == ck.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// All возвращает действующие cookies в стабильном порядке.
+func (j *CookieJar) All() []Cookie {
+	now := time.Now()
+	j.mu.Lock()
+	out := make([]Cookie, 0, len(j.entries))
+	for key, ck := range j.entries {
+		if ck.expired(now) {
+			delete(j.entries, key)
+			continue
+		}
+		out = append(out, ck)
+	}
+	j.mu.Unlock()
+	sort.Slice(out, func(a, b int) bool {
+		if out[a].Domain != out[b].Domain {
+			return out[a].Domain < out[b].Domain
+		}
+		if out[a].Path != out[b].Path {
+			return out[a].Path < out[b].Path
+		}
+		return out[a].Name < out[b].Name
+	})
+	return out
+}
+
+// Load добавляет cookies (например, из Snapshot.Cookies); истёкшие пропускаются.
+// Возвращает число добавленных cookies.
+func (j *CookieJar) Load(cookies []Cookie) int {
+	now, n := time.Now(), 0
+	for _, ck := range cookies {
+		if ck.expired(now) || ck.Domain == "" {
+			continue
+		}
+		hc := &http.Cookie{Name: ck.Name, Value: ck.Value, Path: ck.Path, Expires: ck.Expires, Secure: ck.Secure, HttpOnly: ck.HttpOnly}
+		if ck.IncludeSubdomains {
+			hc.Domain = ck.Domain
+		}
+		path := ck.Path
+		if path == "" {
+			path = "/"
+		}
+		j.SetCookies(&url.URL{Scheme: "https", Host: ck.Domain, Path: path}, []*http.Cookie{hc})
+		n++
+	}
+	return n
+}
+
+// ImportNetscape загружает cookies из файла формата Netscape cookies.txt
+// (экспорт браузерных расширений, curl -c). Возвращает число загруженных cookies.
+func (j *CookieJar) ImportNetscape(r io.Reader) (int, error) {
+	cookies, err := ParseNetscapeCookies(r)
+	if err != nil {
+		return 0, err
+	}
+	return j.Load(cookies), nil
+}
+
+// ParseNetscapeCookies разбирает формат Netscape cookies.txt:
+// domain, include subdomains, path, secure, expires (unix), name, value — через табуляцию.
+// Префикс "#HttpOnly_" у домена помечает HttpOnly cookie, прочие строки с # — комментарии.
+func ParseNetscapeCookies(r io.Reader) ([]Cookie, error) {
+	var out []Cookie
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimRight(sc.Text(), "\r")
+		httpOnly := false
+		if rest, ok := strings.CutPrefix(text, "#HttpOnly_"); ok {
+			text, httpOnly = rest, true
+		}
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		f := strings.Split(text, "\t")
+		if len(f) == 6 { // пустое значение без завершающего таба
+			f = append(f, "")
+		}
+		if len(f) != 7 {
+			return nil, fmt.Errorf("cookies.txt line %d: expected 7 fields, got %d", line, len(f))
+		}
+		exp, err := strconv.ParseInt(f[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cookies.txt line %d: expires: %w", line, err)
+		}
+		ck := Cookie{
+			Domain:            strings.TrimPrefix(strings.ToLower(f[0]), "."),
+			IncludeSubdomains: strings.EqualFold(f[1], "TRUE") || strings.HasPrefix(f[0], "."),
+			Path:              f[2],
+			Secure:            strings.EqualFold(f[3], "TRUE"),
+			Name:              f[5],
+			Value:             f[6],
+			HttpOnly:          httpOnly,
+		}
+		if exp > 0 {
+			ck.Expires = time.Unix(exp, 0)
+		}
+		out = append(out, ck)
+	}
+	return out, sc.Err()
+}
+
+// WriteNetscape выгружает cookies jar в формате Netscape cookies.txt.
+func (j *CookieJar) WriteNetscape(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("# Netscape HTTP Cookie File\n")
+	for _, ck := range j.All() {
+		domain := ck.Domain
+		if ck.IncludeSubdomains {
+			domain = "." + domain
+		}
+		if ck.HttpOnly {
+			domain = "#HttpOnly_" + domain
+		}
+		var exp int64
+		if !ck.Expires.IsZero() {
+			exp = ck.Expires.Unix()
+		}
+		fmt.Fprintf(bw, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", domain, netscapeBool(ck.IncludeSubdomains), ck.Path, netscapeBool(ck.Secure), exp, ck.Name, ck.Value)
+	}
+	return bw.Flush()
+}
+
+func netscapeBool(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func (c Cookie) expired(now time.Time) bool { return !c.Expires.IsZero() && !c.Expires.After(now) }
+
+// defaultCookiePath путь по умолчанию из RFC 6265, раздел 5.1.4.
+func defaultCookiePath(p string) string {
+	if p == "" || p[0] != '/' {
+		return "/"
+	}
+	i := strings.LastIndex(p, "/")
+	if i == 0 {
+		return "/"
+	}
+	return p[:i]
+}
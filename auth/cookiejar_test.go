@@ -0,0 +1,103 @@
+package auth_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+)
+
+func TestCookieJarRoundTrip(t *testing.T) {
+	jar := auth.NewCookieJar()
+	u, _ := url.Parse("https://passport.yandex.ru/auth/welcome")
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "Session_id", Value: "s1", Domain: ".yandex.ru", Path: "/", Secure: true, HttpOnly: true, Expires: time.Now().Add(time.Hour)},
+		{Name: "track", Value: "t1"}, // host-only, путь по умолчанию /auth
+		{Name: "gone", Value: "x", MaxAge: -1},
+	})
+	all := jar.All()
+	if len(all) != 2 {
+		t.Fatalf("expected 2 cookies, got %+v", all)
+	}
+	if all[0].Name != "track" || all[0].Domain != "passport.yandex.ru" || all[0].IncludeSubdomains || all[0].Path != "/auth" {
+		t.Fatalf("host-only cookie %+v", all[0])
+	}
+	if all[1].Domain != "yandex.ru" || !all[1].IncludeSubdomains || !all[1].HttpOnly {
+		t.Fatalf("domain cookie %+v", all[1])
+	}
+
+	data, _ := json.Marshal(all)
+	var restored []auth.Cookie
+	_ = json.Unmarshal(data, &restored)
+	jar2 := auth.NewCookieJar()
+	if n := jar2.Load(restored); n != 2 {
+		t.Fatalf("loaded %d", n)
+	}
+	// domain cookie действует на поддоменах, host-only — нет
+	mp, _ := url.Parse("https://mobileproxy.passport.yandex.net/")
+	music, _ := url.Parse("https://music.yandex.ru/")
+	if got := jar2.Cookies(music); len(got) != 1 || got[0].Name != "Session_id" {
+		t.Fatalf("music cookies %v", got)
+	}
+	if got := jar2.Cookies(mp); len(got) != 0 {
+		t.Fatalf("foreign domain got %v", got)
+	}
+	if got := jar2.Cookies(u); len(got) != 2 {
+		t.Fatalf("passport cookies %v", got)
+	}
+
+	// удаление cookie сервером убирает её из снимка
+	jar2.SetCookies(u, []*http.Cookie{{Name: "Session_id", Domain: ".yandex.ru", Path: "/", MaxAge: -1}})
+	if all := jar2.All(); len(all) != 1 {
+		t.Fatalf("cookie not deleted %+v", all)
+	}
+}
+
+func TestNetscapeCookies(t *testing.T) {
+	exp := time.Now().Add(24 * time.Hour).Unix()
+	txt := "# Netscape HTTP Cookie File\n" +
+		"\n" +
+		"#HttpOnly_.yandex.ru\tTRUE\t/\tTRUE\t" + strconv.FormatInt(exp, 10) + "\tSession_id\tabc\n" +
+		"passport.yandex.ru\tFALSE\t/\tFALSE\t0\tyandexuid\t123\n" +
+		".yandex.ru\tTRUE\t/\tFALSE\t1\told\tx\n" + // истекла
+		"music.yandex.ru\tFALSE\t/\tFALSE\t0\tempty\n"
+	jar := auth.NewCookieJar()
+	n, err := jar.ImportNetscape(strings.NewReader(txt))
+	if err != nil || n != 3 {
+		t.Fatalf("import n=%d err=%v", n, err)
+	}
+	var buf bytes.Buffer
+	if err := jar.WriteNetscape(&buf); err != nil {
+		t.Fatal(err)
+	}
+	again, err := auth.ParseNetscapeCookies(&buf)
+	if err != nil || len(again) != 3 {
+		t.Fatalf("re-parse %d %v", len(again), err)
+	}
+	for _, ck := range again {
+		if ck.Name == "Session_id" && (!ck.HttpOnly || !ck.Secure || !ck.IncludeSubdomains || ck.Expires.Unix() != exp) {
+			t.Fatalf("session cookie lost attributes %+v", ck)
+		}
+	}
+	if _, err := auth.ParseNetscapeCookies(strings.NewReader("bad line\n")); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
+
+func TestCookieJarSkipsRejectedCookies(t *testing.T) {
+	jar := auth.NewCookieJar()
+	u, _ := url.Parse("https://passport.yandex.ru/")
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "ok", Value: "1", Domain: ".yandex.ru", Path: "/"},
+		{Name: "foreign", Value: "2", Domain: ".evil.example", Path: "/"},
+	})
+	if all := jar.All(); len(all) != 1 || all[0].Name != "ok" {
+		t.Fatalf("rejected cookie recorded: %+v", all)
+	}
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"rsc.io/qr"
+)
+
+// qrQuietZone ширина белой рамки вокруг QR в модулях (стандарт требует 4).
+const qrQuietZone = 4
+
+// WriteQRTerminal рисует text как QR-код символами полублоков (▀▄█),
+// по две строки модулей на одну строку терминала.
+// По умолчанию закрашиваются светлые модули (тёмная тема терминала);
+// invert закрашивает тёмные — для светлых тем.
+func WriteQRTerminal(w io.Writer, text string, invert bool) error {
+	code, err := qr.Encode(text, qr.L)
+	if err != nil {
+		return fmt.Errorf("qr encode: %w", err)
+	}
+	// по умолчанию рассчитываем на тёмный фон: закрашиваем светлые модули
+	// (включая рамку — вне матрицы Black() == false)
+	lit := func(x, y int) bool { return !code.Black(x, y) }
+	if invert {
+		lit = code.Black
+	}
+	bw := bufio.NewWriter(w)
+	lo, hi := -qrQuietZone, code.Size+qrQuietZone
+	for y := lo; y < hi; y += 2 {
+		for x := lo; x < hi; x++ {
+			top, bottom := lit(x, y), y+1 < hi && lit(x, y+1)
+			switch {
+			case top && bottom:
+				bw.WriteString("█")
+			case top:
+				bw.WriteString("▀")
+			case bottom:
+				bw.WriteString("▄")
+			default:
+				bw.WriteByte(' ')
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// WriteQRPNG пишет text как PNG-изображение QR-кода; scale — пикселей на модуль (<=0 — 8).
+func WriteQRPNG(w io.Writer, text string, scale int) error {
+	code, err := qr.Encode(text, qr.L)
+	if err != nil {
+		return fmt.Errorf("qr encode: %w", err)
+	}
+	if scale > 0 {
+		code.Scale = scale
+	}
+	_, err = w.Write(code.PNG())
+	return err
+}
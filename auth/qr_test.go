@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWriteQRTerminal(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteQRTerminal(&b, "https://passport.yandex.ru/auth/magic/code/?track_id=1", false); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	width := utf8.RuneCountInString(lines[0])
+	// модулей по высоте вдвое больше строк (последняя может быть половинной)
+	if rows := len(lines); rows != (width+1)/2 {
+		t.Fatalf("got %d rows for width %d", rows, width)
+	}
+	// верхняя строка — светлая рамка, в тёмной теме закрашена целиком
+	if lines[0] != strings.Repeat("█", width) {
+		t.Fatalf("quiet zone not lit: %q", lines[0])
+	}
+	b.Reset()
+	if err := WriteQRTerminal(&b, "x", true); err != nil {
+		t.Fatal(err)
+	}
+	if first := strings.SplitN(b.String(), "\n", 2)[0]; strings.TrimSpace(first) != "" {
+		t.Fatalf("inverted quiet zone not blank: %q", first)
+	}
+}
+
+func TestWriteQRPNG(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteQRPNG(&b, "hello", 2); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	// версия 1 (21 модуль) + рамка 4 с каждой стороны, по 2 пикселя
+	if w := img.Bounds().Dx(); w != (21+8)*2 {
+		t.Fatalf("width %d", w)
+	}
+}
@@ -1,25 +1,118 @@
 package auth
 
-import "net/url"
+import (
+	"net/url"
+	"sync"
+	"time"
+)
 
 // Storage хранит данные авторизации и сетевые настройки.
+//
+// Методы Storage безопасны для конкурентного использования. Прямой доступ к
+// экспортируемым полям не синхронизирован и оставлен только для совместимости:
+// пока клиент выполняет запросы, используйте методы.
 type Storage struct {
-	Token        string
-	DeviceID     string
-	Proxy        *url.URL
-	User         *User
+	mu sync.RWMutex
+
+	// Deprecated: используйте GetToken / SetToken.
+	Token string
+	// Deprecated: используйте GetDeviceID / SetDeviceID.
+	DeviceID string
+	// Deprecated: используйте GetProxy / SetProxy.
+	Proxy *url.URL
+	// Deprecated: используйте UID, Login, SetUid, SetLogin.
+	User *User
+	// Deprecated: используйте Authorized / SetAuthorized.
 	IsAuthorized bool
-	AuthToken    *AuthToken
-	AccessToken  *AccessToken
+	// Deprecated: используйте Session / SetSession.
+	AuthToken *AuthToken
+	// Deprecated: используйте GetAccessToken / SetAccessToken.
+	AccessToken *AccessToken
+
+	// tokenExpiresAt момент истечения Token (нулевое значение — срок неизвестен);
+	// читается через Credentials, задаётся через SetToken.
+	tokenExpiresAt time.Time
 }
 
 // New создаёт новое хранилище с опциональным токеном.
 func New(token string) *Storage {
-	return &Storage{Token: token, DeviceID: "go-sdk", User: &User{}}
+	return &Storage{Token: token, DeviceID: DefaultDeviceID, User: &User{}}
+}
+
+// Credentials согласованный снимок данных, нужных для одного запроса.
+type Credentials struct {
+	Token     string
+	ExpiresAt time.Time
+	DeviceID  string
+	Uid       string
+}
+
+// Credentials атомарно читает токен, device id и uid.
+func (s *Storage) Credentials() Credentials {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cr := Credentials{Token: s.Token, ExpiresAt: s.tokenExpiresAt, DeviceID: s.DeviceID}
+	if s.User != nil {
+		cr.Uid = s.User.Uid
+	}
+	return cr
+}
+
+// GetToken возвращает текущий OAuth токен.
+func (s *Storage) GetToken() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Token
+}
+
+// SetToken заменяет токен и срок его действия (нулевой expiresAt — срок неизвестен).
+func (s *Storage) SetToken(token string, expiresAt time.Time) {
+	s.mu.Lock()
+	s.Token, s.tokenExpiresAt = token, expiresAt
+	s.mu.Unlock()
+}
+
+// GetDeviceID возвращает идентификатор устройства.
+func (s *Storage) GetDeviceID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.DeviceID
+}
+
+// SetDeviceID устанавливает идентификатор устройства.
+func (s *Storage) SetDeviceID(id string) {
+	s.mu.Lock()
+	s.DeviceID = id
+	s.mu.Unlock()
+}
+
+// GetProxy возвращает настроенный прокси (nil — без прокси).
+func (s *Storage) GetProxy() *url.URL {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Proxy
 }
 
 // SetProxy настраивает прокси.
-func (s *Storage) SetProxy(u *url.URL) { s.Proxy = u }
+func (s *Storage) SetProxy(u *url.URL) {
+	s.mu.Lock()
+	s.Proxy = u
+	s.mu.Unlock()
+}
+
+// Authorized сообщает, подтверждён ли токен запросом account/status.
+func (s *Storage) Authorized() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.IsAuthorized
+}
+
+// SetAuthorized отмечает хранилище авторизованным (или нет).
+func (s *Storage) SetAuthorized(ok bool) {
+	s.mu.Lock()
+	s.IsAuthorized = ok
+	s.mu.Unlock()
+}
 
 // User представляет текущего авторизованного пользователя.
 type User struct {
@@ -33,15 +126,100 @@ type AuthToken struct {
 	TrackId   string
 }
 
+// Session возвращает копию состояния многошаговой авторизации (нулевое значение, если её нет).
+func (s *Storage) Session() AuthToken {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.AuthToken == nil {
+		return AuthToken{}
+	}
+	return *s.AuthToken
+}
+
+// SetSession заменяет состояние многошаговой авторизации.
+func (s *Storage) SetSession(a AuthToken) {
+	s.mu.Lock()
+	s.AuthToken = &a
+	s.mu.Unlock()
+}
+
 // AccessToken хранит полученный OAuth music token.
 type AccessToken struct {
-	AccessToken string
-	TokenType   string
-	ExpiresIn   int
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type,omitempty"`
+	ExpiresIn   int       `json:"expires_in,omitempty"`
+	IssuedAt    time.Time `json:"issued_at"`
+}
+
+// ExpiresAt возвращает момент истечения токена (нулевое значение, если срок неизвестен).
+func (a *AccessToken) ExpiresAt() time.Time {
+	if a == nil || a.ExpiresIn <= 0 || a.IssuedAt.IsZero() {
+		return time.Time{}
+	}
+	return a.IssuedAt.Add(time.Duration(a.ExpiresIn) * time.Second)
+}
+
+// GetAccessToken возвращает копию сохранённого x-token (nil, если его нет).
+func (s *Storage) GetAccessToken() *AccessToken {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.AccessToken == nil {
+		return nil
+	}
+	a := *s.AccessToken
+	return &a
+}
+
+// SetAccessToken сохраняет копию токена, проставляя IssuedAt (если не задан) для
+// расчёта срока действия; структура вызывающего не меняется.
+func (s *Storage) SetAccessToken(acc *AccessToken) {
+	var cp *AccessToken
+	if acc != nil {
+		a := *acc
+		if a.IssuedAt.IsZero() {
+			a.IssuedAt = time.Now()
+		}
+		cp = &a
+	}
+	s.mu.Lock()
+	s.AccessToken = cp
+	s.mu.Unlock()
+}
+
+// TokenExpiresWithin сообщает, истекает ли Token в ближайшие d (false, если срок неизвестен).
+func (s *Storage) TokenExpiresWithin(d time.Duration) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.tokenExpiresAt.IsZero() {
+		return false
+	}
+	return time.Until(s.tokenExpiresAt) < d
+}
+
+// UID возвращает идентификатор пользователя.
+func (s *Storage) UID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.User == nil {
+		return ""
+	}
+	return s.User.Uid
+}
+
+// Login возвращает логин пользователя.
+func (s *Storage) Login() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.User == nil {
+		return ""
+	}
+	return s.User.Login
 }
 
 // SetUid устанавливает идентификатор пользователя.
 func (s *Storage) SetUid(uid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.User == nil {
 		s.User = &User{}
 	}
@@ -50,6 +228,8 @@ func (s *Storage) SetUid(uid string) {
 
 // SetLogin устанавливает логин пользователя.
 func (s *Storage) SetLogin(login string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.User == nil {
 		s.User = &User{}
 	}
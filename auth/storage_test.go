@@ -0,0 +1,65 @@
+package auth_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+)
+
+// TestStorageConcurrentAccess должен проходить под -race; токен и срок меняются согласованно.
+func TestStorageConcurrentAccess(t *testing.T) {
+	st := auth.New("")
+	base := time.Unix(1700000000, 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				st.SetToken(fmt.Sprint(j), base.Add(time.Duration(j)*time.Second))
+				st.SetUid(fmt.Sprint(i))
+				st.SetSession(auth.AuthToken{TrackId: fmt.Sprint(j)})
+				st.SetAccessToken(&auth.AccessToken{AccessToken: "x"})
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				cr := st.Credentials()
+				if cr.Token != "" && cr.Token != fmt.Sprint(int64(cr.ExpiresAt.Sub(base)/time.Second)) {
+					t.Errorf("torn credentials: %+v", cr)
+					return
+				}
+				_ = st.Snapshot()
+				_ = st.Session()
+				_ = st.GetAccessToken()
+				_ = st.TokenExpiresWithin(time.Minute)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestStorageAccessorsCopy(t *testing.T) {
+	st := auth.New("tok")
+	in := &auth.AccessToken{AccessToken: "x"}
+	st.SetAccessToken(in)
+	if !in.IssuedAt.IsZero() || st.GetAccessToken().IssuedAt.IsZero() {
+		t.Fatalf("SetAccessToken must stamp its own copy: caller %v", in.IssuedAt)
+	}
+	in.AccessToken = "mutated"
+	acc := st.GetAccessToken()
+	acc.AccessToken = "changed"
+	if st.GetAccessToken().AccessToken != "x" {
+		t.Fatalf("GetAccessToken must return a copy")
+	}
+	if st.Session() != (auth.AuthToken{}) {
+		t.Fatalf("empty session expected")
+	}
+	if cr := st.Credentials(); cr.Token != "tok" || cr.DeviceID != auth.DefaultDeviceID {
+		t.Fatalf("credentials %+v", cr)
+	}
+}
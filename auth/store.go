@@ -0,0 +1,322 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// DefaultDeviceID значение DeviceID, которое New выставляет по умолчанию.
+const DefaultDeviceID = "go-sdk"
+
+// Store постоянное хранилище данных авторизации.
+// Load возвращает (nil, nil), если сохранённых данных ещё нет.
+type Store interface {
+	Load() (*Snapshot, error)
+	Save(snap *Snapshot) error
+}
+
+// Snapshot сериализуемое состояние Storage (плюс cookies http клиента).
+type Snapshot struct {
+	Token       string       `json:"token,omitempty"`
+	DeviceID    string       `json:"deviceId,omitempty"`
+	User        *User        `json:"user,omitempty"`
+	AccessToken *AccessToken `json:"accessToken,omitempty"`
+	// TokenExpiresAt срок действия Token (см. Credentials.ExpiresAt).
+	TokenExpiresAt time.Time `json:"tokenExpiresAt,omitempty"`
+	Cookies        []Cookie  `json:"cookies,omitempty"`
+}
+
+// Cookie сериализуемая cookie из cookie jar клиента.
+type Cookie struct {
+	Domain string `json:"domain"`
+	// IncludeSubdomains cookie задана с атрибутом Domain и действует для поддоменов (иначе host-only).
+	IncludeSubdomains bool      `json:"includeSubdomains,omitempty"`
+	Path              string    `json:"path,omitempty"`
+	Name              string    `json:"name"`
+	Value             string    `json:"value"`
+	Expires           time.Time `json:"expires,omitempty"`
+	Secure            bool      `json:"secure,omitempty"`
+	HttpOnly          bool      `json:"httpOnly,omitempty"`
+}
+
+// Snapshot возвращает копию сохраняемых полей (без cookies — их добавляет клиент).
+func (s *Storage) Snapshot() *Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snap := &Snapshot{Token: s.Token, DeviceID: s.DeviceID, TokenExpiresAt: s.tokenExpiresAt}
+	if s.User != nil {
+		u := *s.User
+		snap.User = &u
+	}
+	if s.AccessToken != nil {
+		a := *s.AccessToken
+		snap.AccessToken = &a
+	}
+	return snap
+}
+
+// Restore заполняет пустые поля хранилища из снимка; явно заданные значения не перетираются.
+func (s *Storage) Restore(snap *Snapshot) {
+	if snap == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Token == "" {
+		s.Token = snap.Token
+		s.tokenExpiresAt = snap.TokenExpiresAt
+	}
+	if snap.DeviceID != "" && (s.DeviceID == "" || s.DeviceID == DefaultDeviceID) {
+		s.DeviceID = snap.DeviceID
+	}
+	if snap.User != nil {
+		if s.User == nil || s.User.Uid == "" {
+			u := *snap.User
+			s.User = &u
+		}
+	}
+	if s.AccessToken == nil && snap.AccessToken != nil {
+		a := *snap.AccessToken
+		s.AccessToken = &a
+	}
+}
+
+// FileStore хранит Snapshot в JSON файле с правами 0600.
+// При непустой парольной фразе содержимое шифруется (scrypt + NaCl secretbox).
+type FileStore struct {
+	path       string
+	passphrase string
+}
+
+// NewFileStore создаёт файловое хранилище без шифрования.
+func NewFileStore(path string) *FileStore { return &FileStore{path: path} }
+
+// NewEncryptedFileStore создаёт файловое хранилище, зашифрованное парольной фразой.
+func NewEncryptedFileStore(path, passphrase string) *FileStore {
+	return &FileStore{path: path, passphrase: passphrase}
+}
+
+// ErrDecrypt возвращается при неверной парольной фразе или повреждённом файле.
+var ErrDecrypt = errors.New("auth store: decryption failed")
+
+// sealed формат зашифрованного файла.
+type sealed struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	LogN    int    `json:"logN"`
+	Salt    string `json:"salt"`
+	Nonce   string `json:"nonce"`
+	Box     string `json:"box"`
+}
+
+const sealLogN = 15
+
+// Load читает снимок из файла.
+func (f *FileStore) Load() (*Snapshot, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if f.passphrase != "" {
+		if data, err = f.open(data); err != nil {
+			return nil, err
+		}
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("auth store: decode: %w", err)
+	}
+	return &snap, nil
+}
+
+// Save атомарно записывает снимок (временный файл + rename).
+func (f *FileStore) Save(snap *Snapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	if f.passphrase != "" {
+		if data, err = f.seal(data); err != nil {
+			return err
+		}
+	}
+	dir := filepath.Dir(f.path)
+	tmp, err := os.CreateTemp(dir, ".auth-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+func (f *FileStore) key(salt []byte, logN int) (*[32]byte, error) {
+	k, err := scrypt.Key([]byte(f.passphrase), salt, 1<<logN, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	var key [32]byte
+	copy(key[:], k)
+	return &key, nil
+}
+
+func (f *FileStore) seal(plain []byte) ([]byte, error) {
+	salt := make([]byte, 16)
+	var nonce [24]byte
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+	key, err := f.key(salt, sealLogN)
+	if err != nil {
+		return nil, err
+	}
+	box := secretbox.Seal(nil, plain, &nonce, key)
+	enc := base64.StdEncoding.EncodeToString
+	return json.MarshalIndent(sealed{Version: 1, KDF: "scrypt", LogN: sealLogN, Salt: enc(salt), Nonce: enc(nonce[:]), Box: enc(box)}, "", "  ")
+}
+
+func (f *FileStore) open(data []byte) ([]byte, error) {
+	var env sealed
+	if err := json.Unmarshal(data, &env); err != nil || env.Version != 1 || env.KDF != "scrypt" {
+		return nil, ErrDecrypt
+	}
+	dec := base64.StdEncoding.DecodeString
+	salt, err1 := dec(env.Salt)
+	nonce, err2 := dec(env.Nonce)
+	box, err3 := dec(env.Box)
+	if err1 != nil || err2 != nil || err3 != nil || len(nonce) != 24 || env.LogN < 1 || env.LogN > 30 {
+		return nil, ErrDecrypt
+	}
+	key, err := f.key(salt, env.LogN)
+	if err != nil {
+		return nil, err
+	}
+	var n [24]byte
+	copy(n[:], nonce)
+	plain, ok := secretbox.Open(nil, box, &n, key)
+	if !ok {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
+
+// EnvStore читает данные авторизации из переменных окружения с префиксом
+// (по умолчанию YANDEX_MUSIC_): TOKEN, DEVICE_ID, UID, LOGIN, ACCESS_TOKEN и
+// STATE — base64 JSON полного Snapshot (включая cookies). Отдельные переменные
+// имеют приоритет над STATE. Save меняет окружение только текущего процесса
+// (например, для передачи дочерним процессам).
+type EnvStore struct {
+	Prefix string
+}
+
+// NewEnvStore создаёт хранилище на переменных окружения; пустой prefix — YANDEX_MUSIC_.
+func NewEnvStore(prefix string) *EnvStore {
+	if prefix == "" {
+		prefix = "YANDEX_MUSIC_"
+	}
+	return &EnvStore{Prefix: prefix}
+}
+
+// Load собирает Snapshot из окружения; (nil, nil), если переменные не заданы.
+func (e *EnvStore) Load() (*Snapshot, error) {
+	snap := &Snapshot{}
+	found := false
+	if raw := os.Getenv(e.Prefix + "STATE"); raw != "" {
+		data, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil {
+			return nil, fmt.Errorf("auth store: %sSTATE: %w", e.Prefix, err)
+		}
+		if err := json.Unmarshal(data, snap); err != nil {
+			return nil, fmt.Errorf("auth store: %sSTATE: %w", e.Prefix, err)
+		}
+		found = true
+	}
+	set := func(name string, dst *string) {
+		if v := os.Getenv(e.Prefix + name); v != "" {
+			*dst = v
+			found = true
+		}
+	}
+	stateToken := snap.Token
+	set("TOKEN", &snap.Token)
+	if snap.Token != stateToken {
+		// срок из STATE относится к другому токену
+		snap.TokenExpiresAt = time.Time{}
+	}
+	set("DEVICE_ID", &snap.DeviceID)
+	var uid, login, access string
+	set("UID", &uid)
+	set("LOGIN", &login)
+	set("ACCESS_TOKEN", &access)
+	if uid != "" || login != "" {
+		if snap.User == nil {
+			snap.User = &User{}
+		}
+		if uid != "" {
+			snap.User.Uid = uid
+		}
+		if login != "" {
+			snap.User.Login = login
+		}
+	}
+	if access != "" {
+		if snap.AccessToken == nil {
+			snap.AccessToken = &AccessToken{}
+		}
+		snap.AccessToken.AccessToken = access
+	}
+	if !found {
+		return nil, nil
+	}
+	return snap, nil
+}
+
+// Save записывает Snapshot в окружение текущего процесса.
+func (e *EnvStore) Save(snap *Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	vars := map[string]string{
+		"STATE":     base64.StdEncoding.EncodeToString(data),
+		"TOKEN":     snap.Token,
+		"DEVICE_ID": snap.DeviceID,
+	}
+	if snap.User != nil {
+		vars["UID"], vars["LOGIN"] = snap.User.Uid, snap.User.Login
+	}
+	if snap.AccessToken != nil {
+		vars["ACCESS_TOKEN"] = snap.AccessToken.AccessToken
+	}
+	for k, v := range vars {
+		if err := os.Setenv(e.Prefix+k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
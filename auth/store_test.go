@@ -0,0 +1,105 @@
+package auth_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+)
+
+func sampleSnapshot() *auth.Snapshot {
+	return &auth.Snapshot{
+		Token:       "tok",
+		DeviceID:    "dev",
+		User:        &auth.User{Uid: "42", Login: "me"},
+		AccessToken: &auth.AccessToken{AccessToken: "x-token"},
+		Cookies:     []auth.Cookie{{Domain: "passport.yandex.ru", Path: "/", Name: "Session_id", Value: "s"}},
+	}
+}
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auth.json")
+	st := auth.NewFileStore(path)
+	if snap, err := st.Load(); err != nil || snap != nil {
+		t.Fatalf("expected empty store got %+v %v", snap, err)
+	}
+	if err := st.Save(sampleSnapshot()); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if fi.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 got %v", fi.Mode().Perm())
+	}
+	snap, err := st.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if snap.Token != "tok" || snap.User.Uid != "42" || snap.AccessToken.AccessToken != "x-token" || len(snap.Cookies) != 1 {
+		t.Fatalf("unexpected snapshot %+v", snap)
+	}
+}
+
+func TestEncryptedFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auth.sealed")
+	if err := auth.NewEncryptedFileStore(path, "secret").Save(sampleSnapshot()); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	raw, _ := os.ReadFile(path)
+	if strings.Contains(string(raw), "x-token") {
+		t.Fatalf("token stored in plaintext")
+	}
+	if _, err := auth.NewEncryptedFileStore(path, "wrong").Load(); !errors.Is(err, auth.ErrDecrypt) {
+		t.Fatalf("expected ErrDecrypt got %v", err)
+	}
+	snap, err := auth.NewEncryptedFileStore(path, "secret").Load()
+	if err != nil || snap.Token != "tok" {
+		t.Fatalf("load: %+v %v", snap, err)
+	}
+}
+
+func TestEnvStore(t *testing.T) {
+	st := auth.NewEnvStore("YMTEST_")
+	t.Setenv("YMTEST_TOKEN", "")
+	t.Setenv("YMTEST_STATE", "")
+	if snap, err := st.Load(); err != nil || snap != nil {
+		t.Fatalf("expected empty got %+v %v", snap, err)
+	}
+	saved := sampleSnapshot()
+	saved.TokenExpiresAt = time.Now().Add(time.Minute)
+	if err := st.Save(saved); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if snap, err := st.Load(); err != nil || snap.TokenExpiresAt.IsZero() {
+		t.Fatalf("expiry of own token lost: %+v %v", snap, err)
+	}
+	t.Setenv("YMTEST_TOKEN", "override")
+	snap, err := st.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if snap.Token != "override" || snap.User.Login != "me" || len(snap.Cookies) != 1 {
+		t.Fatalf("unexpected snapshot %+v", snap)
+	}
+	// срок из STATE относился к прежнему токену
+	if !snap.TokenExpiresAt.IsZero() {
+		t.Fatalf("override token inherited expiry %v", snap.TokenExpiresAt)
+	}
+}
+
+func TestRestoreKeepsExplicitValues(t *testing.T) {
+	s := auth.New("explicit")
+	s.Restore(sampleSnapshot())
+	if s.Token != "explicit" {
+		t.Fatalf("token overwritten: %s", s.Token)
+	}
+	if s.DeviceID != "dev" || s.User.Uid != "42" {
+		t.Fatalf("restore failed %+v", s)
+	}
+}
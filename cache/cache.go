@@ -0,0 +1,30 @@
+// Package cache хранилища ответов API для client.WithCache: LRU в памяти и файловое.
+package cache
+
+import (
+	"net/http"
+	"time"
+)
+
+// Entry сохранённый ответ. Срок свежести решает клиент по Expires;
+// устаревшую запись с ETag можно перепроверить через If-None-Match.
+type Entry struct {
+	Key      string      `json:"key"`
+	Body     []byte      `json:"body"`
+	Header   http.Header `json:"header,omitempty"`
+	ETag     string      `json:"etag,omitempty"`
+	StoredAt time.Time   `json:"storedAt"`
+	Expires  time.Time   `json:"expires"`
+}
+
+// Fresh сообщает, не истёк ли срок записи.
+func (e *Entry) Fresh(now time.Time) bool { return now.Before(e.Expires) }
+
+// Store хранилище записей. Реализации должны быть безопасны для конкурентного использования.
+type Store interface {
+	Get(key string) (*Entry, bool)
+	Set(e *Entry)
+	Delete(key string)
+	// DeletePrefix удаляет все записи, ключ которых начинается с prefix.
+	DeletePrefix(prefix string)
+}
@@ -0,0 +1,62 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/cache"
+)
+
+func testStore(t *testing.T, st cache.Store) {
+	t.Helper()
+	now := time.Now()
+	for _, k := range []string{"albums/1", "albums/2", "users/7/likes/tracks"} {
+		st.Set(&cache.Entry{Key: k, Body: []byte(k), ETag: "e-" + k, Expires: now.Add(time.Minute)})
+	}
+	e, ok := st.Get("albums/1")
+	if !ok || string(e.Body) != "albums/1" || e.ETag != "e-albums/1" || !e.Fresh(now) {
+		t.Fatalf("get: %+v %v", e, ok)
+	}
+	st.DeletePrefix("albums/")
+	if _, ok := st.Get("albums/2"); ok {
+		t.Fatalf("prefix not deleted")
+	}
+	if _, ok := st.Get("users/7/likes/tracks"); !ok {
+		t.Fatalf("unrelated entry deleted")
+	}
+	st.Delete("users/7/likes/tracks")
+	if _, ok := st.Get("users/7/likes/tracks"); ok {
+		t.Fatalf("entry not deleted")
+	}
+}
+
+func TestLRU(t *testing.T) {
+	testStore(t, cache.NewLRU(0))
+
+	lru := cache.NewLRU(2)
+	lru.Set(&cache.Entry{Key: "a"})
+	lru.Set(&cache.Entry{Key: "b"})
+	lru.Get("a") // b становится самым старым
+	lru.Set(&cache.Entry{Key: "c"})
+	if _, ok := lru.Get("b"); ok || lru.Len() != 2 {
+		t.Fatalf("b must be evicted")
+	}
+	if _, ok := lru.Get("a"); !ok {
+		t.Fatalf("a evicted")
+	}
+}
+
+func TestDisk(t *testing.T) {
+	dir := t.TempDir()
+	d, err := cache.NewDisk(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, d)
+
+	d.Set(&cache.Entry{Key: "tracks", Body: []byte("x")})
+	reopened, _ := cache.NewDisk(dir)
+	if e, ok := reopened.Get("tracks"); !ok || string(e.Body) != "x" {
+		t.Fatalf("entry not persisted")
+	}
+}
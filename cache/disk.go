@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Disk файловое хранилище: по JSON файлу на запись в каталоге dir.
+type Disk struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewDisk создаёт (при необходимости) каталог dir и возвращает хранилище.
+func NewDisk(dir string) (*Disk, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &Disk{dir: dir}, nil
+}
+
+func (d *Disk) file(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get читает запись; повреждённые файлы считаются промахом.
+func (d *Disk) Get(key string) (*Entry, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, err := readEntry(d.file(key))
+	if err != nil || e.Key != key {
+		return nil, false
+	}
+	return e, true
+}
+
+// Set атомарно записывает запись (временный файл + rename).
+func (d *Disk) Set(e *Entry) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tmp, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), d.file(e.Key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// Delete удаляет запись.
+func (d *Disk) Delete(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	os.Remove(d.file(key))
+}
+
+// DeletePrefix просматривает все записи каталога и удаляет подходящие.
+func (d *Disk) DeletePrefix(prefix string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	files, _ := filepath.Glob(filepath.Join(d.dir, "*.json"))
+	for _, f := range files {
+		if e, err := readEntry(f); err != nil || strings.HasPrefix(e.Key, prefix) {
+			os.Remove(f)
+		}
+	}
+}
+
+func readEntry(path string) (*Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
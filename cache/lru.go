@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+)
+
+// LRU хранилище в памяти с вытеснением давно не использованных записей.
+type LRU struct {
+	max int
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+// NewLRU создаёт LRU на maxEntries записей (<=0 — без ограничения).
+func NewLRU(maxEntries int) *LRU {
+	return &LRU{max: maxEntries, ll: list.New(), items: map[string]*list.Element{}}
+}
+
+// Get возвращает запись и помечает её как недавно использованную.
+func (c *LRU) Get(key string) (*Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*Entry), true
+}
+
+// Set добавляет или заменяет запись.
+func (c *LRU) Set(e *Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[e.Key]; ok {
+		el.Value = e
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[e.Key] = c.ll.PushFront(e)
+	if c.max > 0 && c.ll.Len() > c.max {
+		last := c.ll.Back()
+		c.ll.Remove(last)
+		delete(c.items, last.Value.(*Entry).Key)
+	}
+}
+
+// Delete удаляет запись.
+func (c *LRU) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.ll.Remove(el)
+		delete(c.items, key)
+	}
+}
+
+// DeletePrefix удаляет записи с ключом, начинающимся с prefix.
+func (c *LRU) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, el := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.ll.Remove(el)
+			delete(c.items, key)
+		}
+	}
+}
+
+// Len количество записей.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
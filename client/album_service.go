@@ -1,7 +1,6 @@
 package client
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -23,18 +22,20 @@ func (s *AlbumService) Get(ctx context.Context, id string) (*models.Response[alb
 	return doJSON[album.Album](s.c, ctx, http.MethodGet, p, nil, nil)
 }
 
-// GetMany returns multiple albums (POST /albums form album-ids=...)
+// GetMany returns multiple albums (POST /albums form album-ids=...).
+// Большие списки разбиваются на чанки (WithBatching); результат в порядке ids.
 func (s *AlbumService) GetMany(ctx context.Context, ids ...string) (*models.Response[[]album.Album], error) {
+	return batchResponse(s.GetManyReport(ctx, ids...))
+}
+
+func (s *AlbumService) getManyChunk(ctx context.Context, ids []string) ([]album.Album, error) {
 	form := url.Values{}
 	form.Set("album-ids", strings.Join(ids, ","))
-	req, err := s.c.newRequest(ctx, http.MethodPost, "albums", nil, nil)
+	req, err := s.c.newRequest(ctx, http.MethodPost, "albums", nil, form)
 	if err != nil {
 		return nil, err
 	}
-	body := form.Encode()
-	req.Body = io.NopCloser(bytes.NewBufferString(body))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := s.c.http.Do(req)
+	resp, err := s.c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -47,5 +48,5 @@ func (s *AlbumService) GetMany(ctx context.Context, ids ...string) (*models.Resp
 	if err := json.Unmarshal(data, &out); err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return out.Result, nil
 }
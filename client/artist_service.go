@@ -1,7 +1,6 @@
 package client
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -24,18 +23,20 @@ func (s *ArtistService) Get(ctx context.Context, id string) (*models.Response[ar
 	return doJSON[artist.ArtistBriefInfo](s.c, ctx, http.MethodGet, p, nil, nil)
 }
 
-// GetMany artists (POST /artists form artist-Ids=...)
+// GetMany artists (POST /artists form artist-Ids=...).
+// Большие списки разбиваются на чанки (WithBatching); результат в порядке ids.
 func (s *ArtistService) GetMany(ctx context.Context, ids ...string) (*models.Response[[]artist.Artist], error) {
+	return batchResponse(s.GetManyReport(ctx, ids...))
+}
+
+func (s *ArtistService) getManyChunk(ctx context.Context, ids []string) ([]artist.Artist, error) {
 	form := url.Values{}
 	form.Set("artist-Ids", strings.Join(ids, ","))
-	req, err := s.c.newRequest(ctx, http.MethodPost, "artists", nil, nil)
+	req, err := s.c.newRequest(ctx, http.MethodPost, "artists", nil, form)
 	if err != nil {
 		return nil, err
 	}
-	body := form.Encode()
-	req.Body = io.NopCloser(bytes.NewBufferString(body))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := s.c.http.Do(req)
+	resp, err := s.c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +49,7 @@ func (s *ArtistService) GetMany(ctx context.Context, ids ...string) (*models.Res
 	if err := json.Unmarshal(data, &out); err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return out.Result, nil
 }
 
 // GetTracks paginated (GET /artists/{id}/tracks?page=&pageSize=)
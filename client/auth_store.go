@@ -0,0 +1,116 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+)
+
+// cookieHosts хосты, cookies которых сохраняются в Store, если у http клиента собственный jar
+// (не *auth.CookieJar, который умеет перечислять cookies сам).
+var cookieHosts = []string{
+	"yandex.ru",
+	"passport.yandex.ru",
+	"oauth.yandex.ru",
+	"login.yandex.ru",
+	"mobileproxy.passport.yandex.net",
+	"music.yandex.ru",
+}
+
+// LoadError возвращает ошибку загрузки auth.Store, произошедшую в New (nil, если всё хорошо).
+func (c *Client) LoadError() error { return c.loadErr }
+
+// SaveError возвращает ошибку последнего сохранения в auth.Store после входа или обмена
+// токена (Authorize, AuthorizeByCookies, GetAccessToken, авто-обновление); nil, если
+// сохранение прошло успешно.
+func (c *Client) SaveError() error {
+	c.saveMu.Lock()
+	defer c.saveMu.Unlock()
+	return c.saveErr
+}
+
+func (c *Client) setSaveErr(err error) {
+	if err != nil {
+		err = fmt.Errorf("save auth: %w", err)
+	}
+	c.saveMu.Lock()
+	c.saveErr = err
+	c.saveMu.Unlock()
+}
+
+// UID возвращает uid авторизованного пользователя (пустая строка до Authorize).
+func (c *Client) UID() string { return c.auth.UID() }
+
+// CookieJar возвращает сериализуемый jar клиента (nil, если в WithHTTPClient передан свой jar).
+func (c *Client) CookieJar() *auth.CookieJar {
+	jar, _ := c.http.Jar.(*auth.CookieJar)
+	return jar
+}
+
+// ImportCookies загружает cookies из Netscape cookies.txt (экспорт из браузера) и сохраняет их в Store.
+// После импорта сессию passport можно обменять на токен через UserService.AuthorizeByCookies.
+func (c *Client) ImportCookies(r io.Reader) (int, error) {
+	jar := c.CookieJar()
+	if jar == nil {
+		cookies, err := auth.ParseNetscapeCookies(r)
+		if err != nil {
+			return 0, err
+		}
+		setCookies(c.http.Jar, cookies)
+		return len(cookies), c.saveAuth()
+	}
+	n, err := jar.ImportNetscape(r)
+	if err != nil {
+		return 0, err
+	}
+	return n, c.saveAuth()
+}
+
+// loadAuth применяет сохранённый снимок к хранилищу и cookie jar.
+func (c *Client) loadAuth() error {
+	snap, err := c.cfg.Store.Load()
+	if err != nil || snap == nil {
+		return err
+	}
+	c.auth.Restore(snap)
+	if cr := c.auth.Credentials(); cr.Token != "" && cr.Uid != "" {
+		c.auth.SetAuthorized(true)
+	}
+	if jar := c.CookieJar(); jar != nil {
+		jar.Load(snap.Cookies)
+	} else if c.http.Jar != nil {
+		setCookies(c.http.Jar, snap.Cookies)
+	}
+	return nil
+}
+
+// saveAuth сохраняет текущее состояние авторизации в Store (если он задан).
+func (c *Client) saveAuth() error {
+	if c.cfg.Store == nil {
+		return nil
+	}
+	snap := c.auth.Snapshot()
+	if jar := c.CookieJar(); jar != nil {
+		snap.Cookies = jar.All()
+	} else if jar := c.http.Jar; jar != nil {
+		for _, host := range cookieHosts {
+			for _, ck := range jar.Cookies(&url.URL{Scheme: "https", Host: host, Path: "/"}) {
+				snap.Cookies = append(snap.Cookies, auth.Cookie{Domain: host, Path: "/", Name: ck.Name, Value: ck.Value})
+			}
+		}
+	}
+	return c.cfg.Store.Save(snap)
+}
+
+func setCookies(jar http.CookieJar, cookies []auth.Cookie) {
+	for _, ck := range cookies {
+		hc := &http.Cookie{Name: ck.Name, Value: ck.Value, Path: ck.Path, Expires: ck.Expires, Secure: ck.Secure, HttpOnly: ck.HttpOnly}
+		if ck.IncludeSubdomains {
+			hc.Domain = ck.Domain
+		}
+		jar.SetCookies(&url.URL{Scheme: "https", Host: ck.Domain, Path: ck.Path}, []*http.Cookie{hc})
+	}
+}
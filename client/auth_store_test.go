@@ -0,0 +1,146 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/models"
+)
+
+func TestStoreLoadAndSaveOnAuthorize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/account/status" {
+			t.Fatalf("path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "OAuth new-token" {
+			t.Fatalf("auth header %s", r.Header.Get("Authorization"))
+		}
+		res := models.Response[sdk.UserAuthResult]{}
+		res.Result.Account.Uid = "7"
+		res.Result.Account.Login = "user"
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	defer srv.Close()
+
+	store := auth.NewFileStore(filepath.Join(t.TempDir(), "auth.json"))
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithStore(store))
+	if err := c.LoadError(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if err := c.User.Authorize(context.Background(), "new-token"); err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	snap, err := store.Load()
+	if err != nil || snap == nil {
+		t.Fatalf("snapshot not saved: %v", err)
+	}
+	if snap.Token != "new-token" || snap.User.Uid != "7" {
+		t.Fatalf("unexpected snapshot %+v", snap)
+	}
+
+	st := auth.New("")
+	sdk.New(sdk.WithAuthStorage(st), sdk.WithStore(store))
+	if st.GetToken() != "new-token" || st.Login() != "user" || !st.Authorized() {
+		t.Fatalf("storage not restored %+v", st)
+	}
+}
+
+// TestImportCookiesSurvivesRestart cookies.txt -> AuthorizeByCookies -> Store -> новый клиент с теми же cookies.
+func TestImportCookiesSurvivesRestart(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ck, err := r.Cookie("Session_id")
+		if err != nil || ck.Value != "browser" {
+			t.Errorf("%s: session cookie missing: %v", r.URL.Path, err)
+		}
+		switch r.URL.Path {
+		case "/1/bundle/oauth/token_by_sessionid":
+			http.SetCookie(w, &http.Cookie{Name: "yandexuid", Value: "42", Domain: "yandex.net", Path: "/"})
+			_, _ = w.Write([]byte(`{"access_token":"x-token"}`))
+		case "/am":
+			_, _ = w.Write([]byte(`"csrf_token" value="c"`))
+		}
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	store := auth.NewFileStore(filepath.Join(t.TempDir(), "auth.json"))
+	newClient := func() *sdk.Client {
+		return sdk.New(sdk.WithStore(store), sdk.WithHTTPClient(&http.Client{Transport: rewriteTransport{target: u}}))
+	}
+
+	c := newClient()
+	txt := "#HttpOnly_.yandex.ru\tTRUE\t/\tTRUE\t0\tSession_id\tbrowser\n" +
+		".passport.yandex.net\tTRUE\t/\tTRUE\t0\tSession_id\tbrowser\n"
+	if n, err := c.ImportCookies(strings.NewReader(txt)); err != nil || n != 2 {
+		t.Fatalf("import n=%d err=%v", n, err)
+	}
+	if err := c.User.AuthorizeByCookies(context.Background()); err != nil {
+		t.Fatalf("authorize by cookies: %v", err)
+	}
+	snap, _ := store.Load()
+	if len(snap.Cookies) != 3 {
+		t.Fatalf("cookies not persisted: %+v", snap.Cookies)
+	}
+
+	// "перезапуск": cookies из Store снова отправляются в passport
+	c2 := newClient()
+	if err := c2.LoadError(); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = c2.User.CreateAuthSession(context.Background(), "user") // сервер проверяет Session_id
+	if len(c2.CookieJar().All()) != 3 {
+		t.Fatalf("jar not restored")
+	}
+}
+
+func TestAuthorizeSurvivesStoreSaveError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := models.Response[sdk.UserAuthResult]{}
+		res.Result.Account.Uid = "7"
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithStore(failingStore{}))
+	if err := c.User.Authorize(context.Background(), "tok"); err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	if c.UID() != "7" || c.SaveError() == nil {
+		t.Fatalf("uid=%q save error=%v", c.UID(), c.SaveError())
+	}
+}
+
+func TestStoreKeepsCookiesOfInterruptedLogin(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/am":
+			_, _ = w.Write([]byte(`<input name="csrf_token" value="csrf1"/>`))
+		case "/registration-validations/auth/multi_step/start":
+			http.SetCookie(w, &http.Cookie{Name: "track_sess", Value: "abc", Path: "/"})
+			_ = json.NewEncoder(w).Encode(models.AuthTypes{TrackId: "tr", AuthTypes: []string{"password"}})
+		default:
+			t.Errorf("unexpected %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	store := auth.NewFileStore(filepath.Join(t.TempDir(), "auth.json"))
+	newClient := func() *sdk.Client {
+		return sdk.New(sdk.WithStore(store), sdk.WithHTTPClient(&http.Client{Transport: rewriteTransport{target: u}, Jar: auth.NewCookieJar()}))
+	}
+	if _, err := newClient().User.CreateAuthSession(context.Background(), "user"); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	// процесс перезапущен до завершения входа
+	jar := newClient().CookieJar()
+	passport, _ := url.Parse("https://passport.yandex.ru/")
+	if got := jar.Cookies(passport); len(got) != 1 || got[0].Value != "abc" {
+		t.Fatalf("passport session lost: %v", got)
+	}
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/Banjirome/yandex-music-go/album"
+	"github.com/Banjirome/yandex-music-go/artist"
+	"github.com/Banjirome/yandex-music-go/models"
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
+
+// значения по умолчанию для WithBatching
+const (
+	defaultBatchSize        = 200
+	defaultBatchParallelism = 4
+)
+
+// BatchResult результат пакетного запроса: найденные объекты в порядке входных id
+// (повторы id схлопываются) и id, которых нет в ответе сервера.
+type BatchResult[T any] struct {
+	Items    []T
+	NotFound []string
+}
+
+// batchGet делит ids на чанки по Config.BatchSize, запрашивает их параллельно (не более
+// Config.BatchParallelism одновременно) и собирает результат в порядке ids.
+// key приводит входной id к виду, в котором его возвращает idOf (например, "трек:альбом" -> "трек").
+func batchGet[T any](c *Client, ctx context.Context, ids []string, fetch func(ctx context.Context, chunk []string) ([]T, error), idOf func(T) string, key func(string) string) (*BatchResult[T], error) {
+	size, par := c.cfg.BatchSize, c.cfg.BatchParallelism
+	if size <= 0 {
+		size = defaultBatchSize
+	}
+	if par <= 0 {
+		par = defaultBatchParallelism
+	}
+	var chunks [][]string
+	for start := 0; start < len(ids); start += size {
+		chunks = append(chunks, ids[start:min(start+size, len(ids))])
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([][]T, len(chunks))
+	errs := make([]error, len(chunks))
+	sem := make(chan struct{}, par)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk []string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+			if results[i], errs[i] = fetch(ctx, chunk); errs[i] != nil {
+				cancel()
+			}
+		}(i, chunk)
+	}
+	wg.Wait()
+	var firstErr error
+	for _, err := range errs {
+		// отмена соседних чанков вторична по отношению к исходной ошибке
+		if err != nil && (firstErr == nil || errors.Is(firstErr, context.Canceled)) {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	byID := map[string]T{}
+	for _, items := range results {
+		for _, it := range items {
+			byID[idOf(it)] = it
+		}
+	}
+	out := &BatchResult[T]{Items: make([]T, 0, len(ids))}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		k := key(id)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		if it, ok := byID[k]; ok {
+			out.Items = append(out.Items, it)
+		} else {
+			out.NotFound = append(out.NotFound, id)
+		}
+	}
+	return out, nil
+}
+
+func sameID(id string) string { return id }
+
+// trackKeyID "id:albumId" -> "id".
+func trackKeyID(key string) string {
+	id, _, _ := strings.Cut(key, ":")
+	return id
+}
+
+// GetReport как Get, но с отчётом о ненайденных id.
+func (s *TrackService) GetReport(ctx context.Context, ids ...string) (*BatchResult[Track], error) {
+	return batchGet(s.c, ctx, ids, s.getChunk, func(t Track) string { return t.ID }, trackKeyID)
+}
+
+// GetManyReport как GetMany, но с отчётом о ненайденных id.
+func (s *AlbumService) GetManyReport(ctx context.Context, ids ...string) (*BatchResult[album.Album], error) {
+	return batchGet(s.c, ctx, ids, s.getManyChunk, func(a album.Album) string { return a.ID }, sameID)
+}
+
+// GetManyReport как GetMany, но с отчётом о ненайденных id.
+func (s *ArtistService) GetManyReport(ctx context.Context, ids ...string) (*BatchResult[artist.Artist], error) {
+	return batchGet(s.c, ctx, ids, s.getManyChunk, func(a artist.Artist) string { return a.ID }, sameID)
+}
+
+// GetBatchReport как GetBatch, но с отчётом о ненайденных плейлистах (в NotFound — "uid:kind").
+func (s *PlaylistService) GetBatchReport(ctx context.Context, pairs [][2]string) (*BatchResult[playlist.Playlist], error) {
+	ids := make([]string, 0, len(pairs))
+	for _, pr := range pairs {
+		ids = append(ids, pr[0]+":"+pr[1])
+	}
+	return batchGet(s.c, ctx, ids, s.getBatchChunk, playlistKey, sameID)
+}
+
+// playlistKey "uid:kind" плейлиста (uid владельца, если он есть).
+func playlistKey(pl playlist.Playlist) string {
+	uid := pl.Uid
+	if pl.Owner != nil && pl.Owner.Uid != "" {
+		uid = pl.Owner.Uid
+	}
+	return uid + ":" + pl.Kind
+}
+
+// batchResponse оборачивает найденные объекты в Response, как возвращали методы до разбиения на чанки.
+func batchResponse[T any](r *BatchResult[T], err error) (*models.Response[[]T], error) {
+	if err != nil {
+		return nil, err
+	}
+	return &models.Response[[]T]{Result: r.Items}, nil
+}
@@ -0,0 +1,110 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/models"
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
+
+func TestTrackGetChunked(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	var mu sync.Mutex
+	var chunks []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		_ = r.ParseForm()
+		ids := strings.Split(r.PostForm.Get("track-ids"), ",")
+		mu.Lock()
+		chunks = append(chunks, len(ids))
+		mu.Unlock()
+		// сервер отдаёт в обратном порядке и молча пропускает "404"
+		var out models.Response[[]sdk.Track]
+		for i := len(ids) - 1; i >= 0; i-- {
+			id, _, _ := strings.Cut(ids[i], ":")
+			if id != "404" {
+				out.Result = append(out.Result, sdk.Track{ID: id})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithBatching(3, 2))
+	ids := []string{"1", "2:20", "404", "4", "5", "6", "7", "2"}
+	res, err := c.Track.GetReport(context.Background(), ids...)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	var got []string
+	for _, tr := range res.Items {
+		got = append(got, tr.ID)
+	}
+	if want := []string{"1", "2", "4", "5", "6", "7"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("order %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(res.NotFound, []string{"404"}) {
+		t.Fatalf("not found %v", res.NotFound)
+	}
+	if len(chunks) != 3 || maxInFlight.Load() > 2 {
+		t.Fatalf("chunks %v, max parallel %d", chunks, maxInFlight.Load())
+	}
+	// старая сигнатура возвращает те же объекты
+	plain, err := c.Track.Get(context.Background(), ids...)
+	if err != nil || len(plain.Result) != 6 {
+		t.Fatalf("get: %v %d", err, len(plain.Result))
+	}
+}
+
+func TestBatchChunkError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if strings.Contains(r.PostForm.Get("album-ids"), "bad") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":[]}`))
+	}))
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithBatching(1, 4))
+	if _, err := c.Album.GetMany(context.Background(), "1", "bad", "3"); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Fatalf("expected chunk error, got %v", err)
+	}
+}
+
+func TestPlaylistGetBatchReport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		out := models.Response[[]playlist.Playlist]{Result: []playlist.Playlist{
+			{Kind: "3", Owner: &playlist.Owner{Uid: "7"}},
+			{Kind: "1", Uid: "7"},
+		}}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL))
+	res, err := c.Playlist.GetBatchReport(context.Background(), [][2]string{{"7", "1"}, {"7", "2"}, {"7", "3"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Items) != 2 || res.Items[0].Kind != "1" || res.Items[1].Kind != "3" || !reflect.DeepEqual(res.NotFound, []string{"7:2"}) {
+		t.Fatalf("unexpected %+v", res)
+	}
+}
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/cache"
+)
+
+// CacheRule задаёт TTL для API путей, подходящих под Pattern (синтаксис path.Match,
+// путь без ведущего "/" относительно BaseURL, например "albums/*/with-tracks").
+type CacheRule struct {
+	Pattern string
+	TTL     time.Duration
+	// PerUser ответ зависит от пользователя (персональные подборки, библиотека, плейлисты):
+	// ключ включает хеш заголовка Authorization, поэтому клиенты с общим Store не видят
+	// ответы друг друга.
+	PerUser bool
+}
+
+// DefaultCacheRules каталог (альбомы, исполнители, треки, лендинг) кешируется надолго,
+// библиотека и плейлисты — ненадолго и сбрасываются после изменений. Все правила PerUser:
+// даже в каталоге поле available и региональные ограничения зависят от аккаунта.
+var DefaultCacheRules = []CacheRule{
+	{Pattern: "albums/*/with-tracks", TTL: time.Hour, PerUser: true},
+	{Pattern: "albums", TTL: time.Hour, PerUser: true},
+	{Pattern: "artists/*/brief-info", TTL: time.Hour, PerUser: true},
+	{Pattern: "artists/*/tracks", TTL: time.Hour, PerUser: true},
+	{Pattern: "artists", TTL: time.Hour, PerUser: true},
+	{Pattern: "tracks", TTL: time.Hour, PerUser: true},
+	{Pattern: "tracks/*/supplement", TTL: time.Hour, PerUser: true},
+	{Pattern: "tracks/*/similar", TTL: time.Hour, PerUser: true},
+	{Pattern: "landing3", TTL: 10 * time.Minute, PerUser: true},
+	{Pattern: "users/*/likes/*", TTL: time.Minute, PerUser: true},
+	{Pattern: "users/*/dislikes/*", TTL: time.Minute, PerUser: true},
+	{Pattern: "users/*/playlists/*", TTL: time.Minute, PerUser: true},
+	{Pattern: "users/*/playlists/list", TTL: time.Minute, PerUser: true},
+	{Pattern: "playlists/list", TTL: time.Minute, PerUser: true},
+	{Pattern: "playlists/*", TTL: time.Minute, PerUser: true},
+}
+
+// readOnlyPOST POST запросы, которые только читают данные (form с id) и могут кешироваться.
+var readOnlyPOST = map[string]bool{"tracks": true, "albums": true, "artists": true, "playlists/list": true}
+
+// InvalidateCache удаляет из кеша записи, путь которых начинается с prefix (например, "albums/123").
+func (c *Client) InvalidateCache(prefix string) {
+	if c.cfg.Cache != nil {
+		c.cfg.Cache.DeletePrefix(strings.TrimPrefix(prefix, "/"))
+	}
+}
+
+// apiPath путь запроса относительно BaseURL.
+func (c *Client) apiPath(u *url.URL) string {
+	base := "/"
+	if b, err := url.Parse(c.cfg.BaseURL); err == nil && b.Path != "" {
+		base = b.Path
+	}
+	return strings.Trim(strings.TrimPrefix(u.Path, strings.TrimRight(base, "/")), "/")
+}
+
+func (c *Client) cacheRule(method, p string) (CacheRule, bool) {
+	if method != http.MethodGet && !(method == http.MethodPost && readOnlyPOST[p]) {
+		return CacheRule{}, false
+	}
+	for _, r := range c.cfg.CacheRules {
+		if ok, _ := path.Match(r.Pattern, p); ok {
+			return r, r.TTL > 0
+		}
+	}
+	return CacheRule{}, false
+}
+
+// cacheKey: путь идёт первым, чтобы работало удаление по префиксу.
+func (c *Client) cacheKey(req *http.Request, p string, rule CacheRule) (string, error) {
+	key := p + "?" + req.URL.RawQuery + " " + req.Method
+	if req.GetBody != nil {
+		h, err := bodyHash(req)
+		if err != nil {
+			return "", err
+		}
+		key += " " + h
+	}
+	if rule.PerUser {
+		// uid недостаточно: до Authorize он пуст у всех клиентов
+		sum := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+		key += " user=" + hex.EncodeToString(sum[:8])
+	}
+	return key, nil
+}
+
+// doCached обслуживает запрос из кеша или сети (fetch) и сбрасывает кеш после успешных изменений.
+func (c *Client) doCached(req *http.Request) (*http.Response, error) {
+	p := c.apiPath(req.URL)
+	rule, ok := c.cacheRule(req.Method, p)
+	if !ok {
+		resp, err := c.fetch(req)
+		if err == nil && resp.StatusCode < 400 && req.Method != http.MethodGet && !readOnlyPOST[p] {
+			c.invalidateAfter(p)
+		}
+		return resp, err
+	}
+	key, err := c.cacheKey(req, p, rule)
+	if err != nil {
+		return c.fetch(req)
+	}
+	now := time.Now()
+	old, hit := c.cfg.Cache.Get(key)
+	if hit && old.Fresh(now) {
+		return cachedResponse(req, old), nil
+	}
+	if hit && old.ETag != "" {
+		req.Header.Set("If-None-Match", old.ETag)
+	}
+	resp, err := c.fetch(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && hit {
+		resp.Body.Close()
+		e := *old
+		e.StoredAt, e.Expires = now, now.Add(rule.TTL)
+		c.cfg.Cache.Set(&e)
+		return cachedResponse(req, &e), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	h := http.Header{}
+	for _, k := range []string{"Content-Type", "Content-Encoding"} {
+		if v := resp.Header.Get(k); v != "" {
+			h.Set(k, v)
+		}
+	}
+	c.cfg.Cache.Set(&cache.Entry{Key: key, Body: data, Header: h, ETag: resp.Header.Get("ETag"), StoredAt: now, Expires: now.Add(rule.TTL)})
+	return resp, nil
+}
+
+// bodyHash короткий хеш тела запроса (для ключей кеша и coalescing).
+func bodyHash(req *http.Request) (string, error) {
+	if req.GetBody == nil {
+		return "", nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+func cachedResponse(req *http.Request, e *cache.Entry) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// invalidateAfter сбрасывает записи, которые могло изменить успешное изменение по пути p.
+func (c *Client) invalidateAfter(p string) {
+	parts := strings.Split(p, "/")
+	if len(parts) < 3 || parts[0] != "users" {
+		return
+	}
+	user := path.Join("users", parts[1])
+	switch parts[2] {
+	case "likes", "dislikes":
+		// лайк снимает дизлайк и наоборот
+		c.InvalidateCache(user + "/likes")
+		c.InvalidateCache(user + "/dislikes")
+	case "playlists":
+		c.InvalidateCache(user + "/playlists")
+		c.InvalidateCache("playlists/")
+	}
+}
@@ -0,0 +1,166 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+	"github.com/Banjirome/yandex-music-go/cache"
+	sdk "github.com/Banjirome/yandex-music-go/client"
+)
+
+type hitCounter struct {
+	mu   sync.Mutex
+	hits map[string]int
+}
+
+func (h *hitCounter) inc(key string) {
+	h.mu.Lock()
+	h.hits[key]++
+	h.mu.Unlock()
+}
+
+func (h *hitCounter) get(key string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.hits[key]
+}
+
+func TestCacheCatalogAndInvalidation(t *testing.T) {
+	hc := &hitCounter{hits: map[string]int{}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		hc.inc(r.Method + " " + r.URL.Path + " " + r.PostForm.Get("track-ids"))
+		switch r.URL.Path {
+		case "/albums/1/with-tracks":
+			_, _ = w.Write([]byte(`{"result":{"id":"1","title":"A"}}`))
+		case "/tracks":
+			_, _ = w.Write([]byte(`{"result":[{"id":"` + r.PostForm.Get("track-ids") + `"}]}`))
+		case "/users/7/likes/tracks":
+			_, _ = w.Write([]byte(`{"result":{"library":{"uid":7}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"result":{}}`))
+		}
+	}))
+	defer srv.Close()
+	st := auth.New("tok")
+	st.SetUid("7")
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithAuthStorage(st), sdk.WithCache(cache.NewLRU(100)))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		res, err := c.Album.Get(ctx, "1")
+		if err != nil || res.Result.Title != "A" {
+			t.Fatalf("album: %v %+v", err, res)
+		}
+	}
+	if n := hc.get("GET /albums/1/with-tracks "); n != 1 {
+		t.Fatalf("album fetched %d times", n)
+	}
+
+	// POST tracks кешируется с учётом тела
+	for _, id := range []string{"10", "11", "10"} {
+		res, err := c.Track.Get(ctx, id)
+		if err != nil || res.Result[0].ID != id {
+			t.Fatalf("tracks %s: %v", id, err)
+		}
+	}
+	if hc.get("POST /tracks 10") != 1 || hc.get("POST /tracks 11") != 1 {
+		t.Fatalf("tracks hits %v", hc.hits)
+	}
+
+	// лайк сбрасывает закешированный список лайков
+	_, _ = c.Library.LikedTracks(ctx)
+	_, _ = c.Library.LikedTracks(ctx)
+	if _, err := c.Library.AddTrackLike(ctx, "10"); err != nil {
+		t.Fatalf("like: %v", err)
+	}
+	_, _ = c.Library.LikedTracks(ctx)
+	if n := hc.get("GET /users/7/likes/tracks "); n != 2 {
+		t.Fatalf("likes fetched %d times, want 2", n)
+	}
+
+	c.InvalidateCache("albums/1")
+	_, _ = c.Album.Get(ctx, "1")
+	if n := hc.get("GET /albums/1/with-tracks "); n != 2 {
+		t.Fatalf("album not refetched after InvalidateCache: %d", n)
+	}
+}
+
+func TestCacheETagRevalidation(t *testing.T) {
+	var full, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"result":{"id":"1","title":"A"}}`))
+	}))
+	defer srv.Close()
+	// TTL в 1нс — запись сразу устаревает и перепроверяется
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithCache(cache.NewLRU(10), sdk.CacheRule{Pattern: "albums/*/with-tracks", TTL: time.Nanosecond}))
+	for i := 0; i < 3; i++ {
+		res, err := c.Album.Get(context.Background(), "1")
+		if err != nil || res.Result.Title != "A" {
+			t.Fatalf("album: %v", err)
+		}
+	}
+	if full != 1 || notModified != 2 {
+		t.Fatalf("full=%d notModified=%d", full, notModified)
+	}
+}
+
+func TestCacheSharedStoreIsolatesAccounts(t *testing.T) {
+	hc := &hitCounter{hits: map[string]int{}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hc.inc(r.Header.Get("Authorization") + " " + r.URL.Path)
+		if r.URL.Path == "/albums/1/with-tracks" {
+			// доступность каталога зависит от аккаунта (регион, подписка)
+			_, _ = w.Write([]byte(`{"result":{"id":"1","title":"` + r.Header.Get("Authorization") + `"}}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "OAuth tok-a" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":{"kind":"3","title":"private"}}`))
+	}))
+	defer srv.Close()
+	store := cache.NewLRU(100)
+	newClient := func(token string) *sdk.Client {
+		st := auth.New(token)
+		st.SetUid("7")
+		return sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithAuthStorage(st), sdk.WithCache(store))
+	}
+	a, b := newClient("tok-a"), newClient("tok-b")
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := a.Playlist.Get(ctx, "7", "3"); err != nil {
+			t.Fatalf("owner get: %v", err)
+		}
+	}
+	if res, err := b.Playlist.Get(ctx, "7", "3"); err == nil {
+		t.Fatalf("other account got cached private playlist: %+v", res.Result)
+	}
+	if hc.get("OAuth tok-a /users/7/playlists/3") != 1 || hc.get("OAuth tok-b /users/7/playlists/3") != 1 {
+		t.Fatalf("hits %v", hc.hits)
+	}
+	for _, c := range []*sdk.Client{a, b, a, b} {
+		if _, err := c.Album.Get(ctx, "1"); err != nil {
+			t.Fatalf("album: %v", err)
+		}
+	}
+	if res, _ := b.Album.Get(ctx, "1"); res.Result.Title != "OAuth tok-b" {
+		t.Fatalf("catalog entry of another account served: %+v", res.Result)
+	}
+	if hc.get("OAuth tok-a /albums/1/with-tracks") != 1 || hc.get("OAuth tok-b /albums/1/with-tracks") != 1 {
+		t.Fatalf("album hits %v", hc.hits)
+	}
+}
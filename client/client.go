@@ -1,16 +1,17 @@
 package client
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"net/http/cookiejar"
 	"net/url"
 	"path"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Banjirome/yandex-music-go/auth"
@@ -24,6 +25,12 @@ type Client struct {
 	http *http.Client
 	auth *auth.Storage
 
+	loadErr   error
+	saveMu    sync.Mutex
+	saveErr   error
+	refreshMu sync.Mutex
+	flights   flightGroup
+
 	Search   *search.Service
 	Album    *AlbumService
 	Artist   *ArtistService
@@ -53,15 +60,21 @@ func New(opts ...Option) *Client {
 		cfg.HTTPClient = &http.Client{Timeout: 15 * time.Second}
 	}
 	if cfg.HTTPClient.Jar == nil {
-		if jar, err := cookiejar.New(nil); err == nil {
-			cfg.HTTPClient.Jar = jar
-		}
+		cfg.HTTPClient.Jar = auth.NewCookieJar()
 	}
 	if cfg.AuthStorage == nil {
 		cfg.AuthStorage = auth.New("")
 	}
+	if rt, ok := buildTransport(cfg.HTTPClient.Transport, &cfg, cfg.AuthStorage.GetProxy()); ok {
+		hc := *cfg.HTTPClient
+		hc.Transport = rt
+		cfg.HTTPClient = &hc
+	}
 
 	c := &Client{cfg: cfg, http: cfg.HTTPClient, auth: cfg.AuthStorage}
+	if cfg.Store != nil {
+		c.loadErr = c.loadAuth()
+	}
 
 	c.Search = search.NewService(c)
 	c.Album = &AlbumService{c: c}
@@ -108,13 +121,25 @@ func (c *Client) newRequest(ctx context.Context, method, p string, q url.Values,
 		u.RawQuery = q.Encode()
 	}
 
+	// url.Values отправляются как form, multipartFile — как multipart, остальное — JSON.
 	var r io.Reader
-	if body != nil {
-		b, err := json.Marshal(body)
+	contentType := ""
+	switch b := body.(type) {
+	case nil:
+	case url.Values:
+		r = strings.NewReader(b.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	case multipartFile:
+		if r, contentType, err = encodeMultipart(b); err != nil {
+			return nil, err
+		}
+	default:
+		data, err := json.Marshal(b)
 		if err != nil {
 			return nil, err
 		}
-		r = io.NopCloser(strings.NewReader(string(b)))
+		r = bytes.NewReader(data)
+		contentType = "application/json"
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, u.String(), r)
@@ -122,12 +147,10 @@ func (c *Client) newRequest(ctx context.Context, method, p string, q url.Values,
 		return nil, err
 	}
 
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-	if c.auth.Token != "" {
-		req.Header.Set("Authorization", "OAuth "+c.auth.Token)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
+	c.setAuthHeader(req)
 	if c.cfg.UserAgent != "" {
 		req.Header.Set("User-Agent", c.cfg.UserAgent)
 	}
@@ -142,7 +165,7 @@ func doJSON[T any](c *Client, ctx context.Context, method, p string, q url.Value
 	if err != nil {
 		return nil, err
 	}
-	resp, err := c.http.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,53 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/models"
+)
+
+// TestConcurrentAuthorizeAndRequests Authorize меняет токен, пока другие горутины шлют запросы (go test -race).
+func TestConcurrentAuthorizeAndRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "OAuth ")
+		if r.URL.Path == "/account/status" {
+			res := models.Response[sdk.UserAuthResult]{}
+			res.Result.Account.Uid = "uid-" + token
+			_ = json.NewEncoder(w).Encode(res)
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":"ok"}`))
+	}))
+	defer srv.Close()
+
+	st := auth.New("")
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithAuthStorage(st))
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			if err := c.User.Authorize(ctx, fmt.Sprint("t", i)); err != nil {
+				t.Errorf("authorize: %v", err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = c.Track.SendPlayInfo(ctx, sdk.Track{ID: "1"}, "feed", "", "", false, 1, 1)
+		}()
+	}
+	wg.Wait()
+	if !st.Authorized() || !strings.HasPrefix(st.UID(), "uid-t") {
+		t.Fatalf("storage %q authorized=%v", st.UID(), st.Authorized())
+	}
+}
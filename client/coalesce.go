@@ -0,0 +1,118 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// flightGroup объединяет одинаковые одновременные запросы в один HTTP round-trip.
+type flightGroup struct {
+	mu sync.Mutex
+	m  map[string]*flight
+}
+
+type flight struct {
+	done chan struct{}
+	resp *http.Response // без Body
+	body []byte
+	err  error
+}
+
+// noCoalesce помечает контекст запроса, который должен уйти на сервер отдельно —
+// например, перечитывание после изменения не может присоединиться к запросу,
+// начатому до него.
+type noCoalesce struct{}
+
+func withoutCoalescing(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCoalesce{}, true)
+}
+
+// coalesceKey ключ запроса или "" если запрос не объединяется (изменения, неподходящий путь).
+func (c *Client) coalesceKey(req *http.Request) string {
+	if req.Context().Value(noCoalesce{}) != nil {
+		return ""
+	}
+	p := c.apiPath(req.URL)
+	if req.Method != http.MethodGet && !(req.Method == http.MethodPost && readOnlyPOST[p]) {
+		return ""
+	}
+	if len(c.cfg.CoalescePrefixes) > 0 {
+		ok := false
+		for _, prefix := range c.cfg.CoalescePrefixes {
+			prefix = strings.Trim(prefix, "/")
+			if p == prefix || strings.HasPrefix(p, prefix+"/") {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return ""
+		}
+	}
+	h, err := bodyHash(req)
+	if err != nil {
+		return ""
+	}
+	return req.Method + " " + req.URL.String() + " " + h + " " + req.Header.Get("Authorization")
+}
+
+// doCoalesced выполняет fn один раз для всех одновременных запросов с одинаковым ключом;
+// каждый вызывающий получает собственную копию ответа.
+func (c *Client) doCoalesced(req *http.Request, fn func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	key := c.coalesceKey(req)
+	if key == "" {
+		return fn(req)
+	}
+	g := &c.flights
+	g.mu.Lock()
+	if g.m == nil {
+		g.m = map[string]*flight{}
+	}
+	if f, ok := g.m[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-f.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		// запрос ведущего отменён его контекстом — выполняем свой
+		if f.err != nil && (errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded)) && req.Context().Err() == nil {
+			return fn(req)
+		}
+		return f.response(req)
+	}
+	f := &flight{done: make(chan struct{})}
+	g.m[key] = f
+	g.mu.Unlock()
+
+	resp, err := fn(req)
+	if err == nil {
+		f.body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = nil
+		f.resp = resp
+	}
+	f.err = err
+
+	g.mu.Lock()
+	delete(g.m, key)
+	g.mu.Unlock()
+	close(f.done)
+	return f.response(req)
+}
+
+func (f *flight) response(req *http.Request) (*http.Response, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := *f.resp
+	resp.Header = f.resp.Header.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(f.body))
+	resp.Request = req
+	return &resp, nil
+}
@@ -0,0 +1,124 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+	sdk "github.com/Banjirome/yandex-music-go/client"
+)
+
+// slowServer отвечает только после release, считая запросы по пути.
+func slowServer(release <-chan struct{}, hits *sync.Map) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := hits.LoadOrStore(r.Method+" "+r.URL.Path, new(atomic.Int32))
+		n.(*atomic.Int32).Add(1)
+		<-release
+		_, _ = w.Write([]byte(`{"result":{"id":"1","title":"A","revision":1}}`))
+	}))
+}
+
+func hitsOf(hits *sync.Map, key string) int32 {
+	n, ok := hits.Load(key)
+	if !ok {
+		return 0
+	}
+	return n.(*atomic.Int32).Load()
+}
+
+// parallel запускает n вызовов fn, даёт им встать в очередь и отпускает сервер.
+func parallel(n int, release chan struct{}, fn func() error) []error {
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = fn()
+		}(i)
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	return errs
+}
+
+func TestCoalescingSharesRoundTrip(t *testing.T) {
+	release, hits := make(chan struct{}), &sync.Map{}
+	srv := slowServer(release, hits)
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithCoalescing())
+	errs := parallel(10, release, func() error {
+		res, err := c.Album.Get(context.Background(), "1")
+		if err == nil && res.Result.Title != "A" {
+			t.Errorf("unexpected result %+v", res.Result)
+		}
+		return err
+	})
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+	}
+	if n := hitsOf(hits, "GET /albums/1/with-tracks"); n != 1 {
+		t.Fatalf("round-trips %d, want 1", n)
+	}
+}
+
+func TestCoalescingSkipsMutationsAndOtherPrefixes(t *testing.T) {
+	release, hits := make(chan struct{}), &sync.Map{}
+	srv := slowServer(release, hits)
+	defer srv.Close()
+	st := auth.New("tok")
+	st.SetUid("7")
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithAuthStorage(st), sdk.WithCoalescing("tracks"))
+	var i atomic.Int32
+	parallel(6, release, func() error {
+		if i.Add(1)%2 == 0 {
+			_, err := c.Album.Get(context.Background(), "1")
+			return err
+		}
+		_, err := c.Library.AddTrackLike(context.Background(), "1")
+		return err
+	})
+	if n := hitsOf(hits, "GET /albums/1/with-tracks"); n != 3 {
+		t.Fatalf("albums outside prefix coalesced: %d", n)
+	}
+	if n := hitsOf(hits, "POST /users/7/likes/tracks/add-multiple"); n != 3 {
+		t.Fatalf("mutations coalesced: %d", n)
+	}
+}
+
+func TestCoalescingWaiterSurvivesLeaderCancel(t *testing.T) {
+	release, hits := make(chan struct{}), &sync.Map{}
+	srv := slowServer(release, hits)
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithCoalescing())
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, err := c.Album.Get(leaderCtx, "1")
+		leaderDone <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	waiterDone := make(chan error, 1)
+	go func() {
+		_, err := c.Album.Get(context.Background(), "1")
+		waiterDone <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-leaderDone; err == nil {
+		t.Fatalf("leader must fail after cancel")
+	}
+	close(release)
+	if err := <-waiterDone; err != nil {
+		t.Fatalf("waiter failed with leader's cancellation: %v", err)
+	}
+}
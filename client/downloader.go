@@ -6,6 +6,10 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Banjirome/yandex-music-go/playlistfmt"
 )
 
 // Downloader упрощённый аналог DataDownloader из C#.
@@ -54,3 +58,86 @@ func (d *Downloader) ToFile(ctx context.Context, url, filename string) error {
 	_, err = io.Copy(f, rc)
 	return err
 }
+
+// DownloadReport итог Batch: пути сохранённых файлов и ошибки по id трека.
+type DownloadReport struct {
+	Files  map[string]string
+	Failed map[string]error
+	// Skipped треки, файлы которых уже были в каталоге.
+	Skipped []string
+}
+
+// Batch скачивает треки в dir (не более Config.BatchParallelism одновременно).
+// Имена файлов — playlistfmt.FileName, поэтому M3U8 с playlistfmt.LocalFiles(dir)
+// указывает ровно на них. Расширение всегда .mp3: FileLink выбирает mp3, но если
+// у трека его нет, под этим именем сохранится другой кодек (обычно AAC).
+// Повторы одного id качаются один раз, уже существующие файлы не перекачиваются.
+// Ошибки отдельных треков собираются в отчёт; ошибка возвращается только при
+// отмене ctx.
+func (d *Downloader) Batch(ctx context.Context, tracks []Track, dir string) (*DownloadReport, error) {
+	par := d.c.cfg.BatchParallelism
+	if par <= 0 {
+		par = defaultBatchParallelism
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	rep := &DownloadReport{Files: map[string]string{}, Failed: map[string]error{}}
+	var mu sync.Mutex
+	sem := make(chan struct{}, par)
+	var wg sync.WaitGroup
+	seen := make(map[string]bool, len(tracks))
+	for _, t := range tracks {
+		if t.ID != "" {
+			// две горутины с одним dst писали бы в один .part
+			if seen[t.ID] {
+				continue
+			}
+			seen[t.ID] = true
+		}
+		dst := filepath.Join(dir, playlistfmt.FileName(TrackEntry(t)))
+		if _, err := os.Stat(dst); err == nil {
+			mu.Lock()
+			rep.Files[t.ID] = dst
+			rep.Skipped = append(rep.Skipped, t.ID)
+			mu.Unlock()
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return rep, ctx.Err()
+		}
+		wg.Add(1)
+		go func(t Track, dst string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := d.downloadTrack(ctx, t, dst)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				rep.Failed[t.ID] = err
+				return
+			}
+			rep.Files[t.ID] = dst
+		}(t, dst)
+	}
+	wg.Wait()
+	return rep, ctx.Err()
+}
+
+// downloadTrack качает во временный файл и переименовывает, чтобы прерванная
+// загрузка не выглядела готовым файлом при следующем Batch.
+func (d *Downloader) downloadTrack(ctx context.Context, t Track, dst string) error {
+	link, err := d.c.Track.FileLinkTrack(ctx, t)
+	if err != nil {
+		return err
+	}
+	tmp := dst + ".part"
+	if err := d.ToFile(ctx, link, tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
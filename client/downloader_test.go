@@ -0,0 +1,78 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/models"
+	"github.com/Banjirome/yandex-music-go/playlistfmt"
+)
+
+func TestDownloaderBatch(t *testing.T) {
+	var base string
+	var downloads int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/tracks/1:10/download-info" || r.URL.Path == "/tracks/4:40/download-info":
+			_ = json.NewEncoder(w).Encode(models.Response[[]sdk.TrackDownloadInfo]{Result: []sdk.TrackDownloadInfo{{BitrateInKbps: 320, Codec: "mp3", DownloadInfoURL: base + "/fi"}}})
+		case r.URL.Path == "/tracks/2/download-info":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/fi":
+			_ = json.NewEncoder(w).Encode(sdk.StorageDownloadFile{Host: "storage.example", Path: "/a/b", Ts: "1", S: "s"})
+		case strings.HasPrefix(r.URL.Path, "/get-mp3/"):
+			atomic.AddInt32(&downloads, 1)
+			_, _ = w.Write([]byte("ID3-audio"))
+		default:
+			t.Errorf("unexpected %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	base = srv.URL
+	target, _ := url.Parse(srv.URL)
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithHTTPClient(&http.Client{Transport: rewriteTransport{target: target}}))
+
+	dir := t.TempDir()
+	tracks := []sdk.Track{
+		{ID: "1", Title: "One", Artists: []sdk.Artist{{Name: "A"}}, Albums: []sdk.Album{{ID: "10"}}},
+		{ID: "2", Title: "Two"},
+		{ID: "3", Title: "Three"},
+		// та же пара исполнитель/название из другого альбома и повтор id
+		{ID: "4", Title: "One", Artists: []sdk.Artist{{Name: "A"}}, Albums: []sdk.Album{{ID: "40"}}},
+		{ID: "1", Title: "One", Artists: []sdk.Artist{{Name: "A"}}, Albums: []sdk.Album{{ID: "10"}}},
+	}
+	// уже скачанный файл не перекачивается
+	existing := filepath.Join(dir, playlistfmt.FileName(sdk.TrackEntry(tracks[2])))
+	if err := os.WriteFile(existing, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rep, err := c.Downloader.Batch(context.Background(), tracks, dir)
+	if err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	if downloads != 2 || rep.Files["4"] != filepath.Join(dir, "A - One [4].mp3") {
+		t.Fatalf("downloads=%d report %+v", downloads, rep)
+	}
+	if rep.Files["1"] != filepath.Join(dir, "A - One [1].mp3") || rep.Failed["2"] == nil || len(rep.Skipped) != 1 || rep.Files["3"] != existing {
+		t.Fatalf("report %+v", rep)
+	}
+	data, _ := os.ReadFile(rep.Files["1"])
+	if string(data) != "ID3-audio" {
+		t.Fatalf("content %q", data)
+	}
+
+	// M3U8 с LocalFiles указывает на те же пути
+	l := sdk.TracksList("mix", tracks[:1])
+	l.Locate(playlistfmt.LocalFiles(dir))
+	if l.Entries[0].Location != rep.Files["1"] {
+		t.Fatalf("location %q vs %q", l.Entries[0].Location, rep.Files["1"])
+	}
+}
@@ -23,7 +23,7 @@ func (s *GenericService) Get(ctx context.Context, id string) (RawResult, error)
 	if err != nil {
 		return nil, err
 	}
-	r, err := s.c.http.Do(resp)
+	r, err := s.c.do(resp)
 	if err != nil {
 		return nil, err
 	}
@@ -44,7 +44,7 @@ func (s *GenericService) List(ctx context.Context, query url.Values) ([]RawResul
 	if err != nil {
 		return nil, err
 	}
-	r, err := s.c.http.Do(resp)
+	r, err := s.c.do(resp)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,337 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/library"
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
+
+// Export собирает архив библиотеки: лайки и дизлайки треков (с исполнителями, альбомом
+// и длительностью), лайкнутые альбомы, исполнители и плейлисты, а также собственные
+// плейлисты пользователя (Playlist.Favorites) с треками.
+func (s *LibraryService) Export(ctx context.Context) (*library.Archive, error) {
+	uid := s.c.UID()
+	if uid == "" {
+		return nil, fmt.Errorf("user uid not set; call Account.Status first")
+	}
+	a := &library.Archive{Version: library.ArchiveVersion, ExportedAt: time.Now().UTC(), UID: uid}
+
+	liked, err := s.LikedTracks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	disliked, err := s.DislikedTracks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	favs, err := s.c.Playlist.Favorites(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	var lists []playlist.Playlist
+	for _, pl := range favs.Result {
+		full, err := s.c.Playlist.Get(ctx, uid, pl.Kind)
+		if err != nil {
+			return nil, fmt.Errorf("export playlist %s: %w", pl.Kind, err)
+		}
+		lists = append(lists, full.Result)
+	}
+
+	// метаданные всех треков одним пакетным запросом
+	var keys []string
+	for _, lt := range libraryTracks(liked.Result.Library) {
+		keys = append(keys, libraryTrackKey(lt))
+	}
+	for _, lt := range libraryTracks(disliked.Result.Library) {
+		keys = append(keys, libraryTrackKey(lt))
+	}
+	for _, pl := range lists {
+		for _, tc := range pl.Tracks {
+			if k := tc.Key(); k.Id != "" {
+				keys = append(keys, k.String())
+			}
+		}
+	}
+	meta, err := s.c.Track.GetReport(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]Track, len(meta.Items))
+	for _, tr := range meta.Items {
+		byID[tr.ID] = tr
+	}
+	track := func(id, albumID string, ts time.Time) library.ArchiveTrack {
+		at := library.ArchiveTrack{ID: id, AlbumID: albumID, Timestamp: ts}
+		if tr, ok := byID[id]; ok {
+			at.Title, at.DurationMs = tr.Title, tr.DurationMs
+			for _, ar := range tr.Artists {
+				at.Artists = append(at.Artists, ar.Name)
+			}
+			if len(tr.Albums) > 0 {
+				at.Album = tr.Albums[0].Title
+				if at.AlbumID == "" {
+					at.AlbumID = tr.Albums[0].ID
+				}
+			}
+		}
+		return at
+	}
+	for _, lt := range libraryTracks(liked.Result.Library) {
+		a.LikedTracks = append(a.LikedTracks, track(lt.ID, lt.AlbumID, lt.Timestamp))
+	}
+	for _, lt := range libraryTracks(disliked.Result.Library) {
+		a.DislikedTracks = append(a.DislikedTracks, track(lt.ID, lt.AlbumID, lt.Timestamp))
+	}
+	for _, pl := range lists {
+		ap := library.ArchivePlaylist{Kind: pl.Kind, Title: pl.Title, Description: pl.Description}
+		for _, k := range pl.Keys() {
+			ap.Tracks = append(ap.Tracks, track(k.Id, k.AlbumId, time.Time{}))
+		}
+		a.Playlists = append(a.Playlists, ap)
+	}
+
+	albums, err := s.LikedAlbums(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(albums.Result))
+	for i, la := range albums.Result {
+		ids[i] = la.ID
+	}
+	titles := map[string]string{}
+	if len(ids) > 0 {
+		res, err := s.c.Album.GetManyReport(ctx, ids...)
+		if err != nil {
+			return nil, err
+		}
+		for _, al := range res.Items {
+			titles[al.ID] = al.Title
+		}
+	}
+	for _, la := range albums.Result {
+		a.LikedAlbums = append(a.LikedAlbums, library.ArchiveEntity{ID: la.ID, Title: titles[la.ID], Timestamp: la.Timestamp})
+	}
+	artists, err := s.LikedArtistsWithTimestamps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, la := range artists.Result {
+		a.LikedArtists = append(a.LikedArtists, library.ArchiveEntity{ID: la.Artist.ID, Title: la.Artist.Name, Timestamp: la.Timestamp})
+	}
+	pls, err := s.LikedPlaylists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, lp := range pls.Result {
+		a.LikedPlaylists = append(a.LikedPlaylists, library.ArchiveEntity{ID: playlistKey(lp.Playlist), Title: lp.Playlist.Title, Timestamp: lp.Timestamp})
+	}
+	return a, nil
+}
+
+// ImportOptions параметры Import.
+type ImportOptions struct {
+	// DryRun только строит отчёт, ничего не меняя в аккаунте.
+	DryRun bool
+	// SkipPlaylists не создавать плейлисты из архива.
+	SkipPlaylists bool
+}
+
+// ImportReport что Import добавил (или добавил бы в режиме DryRun).
+type ImportReport struct {
+	DryRun         bool
+	LikedTracks    ImportSection
+	DislikedTracks ImportSection
+	LikedAlbums    ImportSection
+	LikedArtists   ImportSection
+	LikedPlaylists ImportSection
+	Playlists      []PlaylistImport
+	// Revision ревизия лайков треков после импорта.
+	Revision int
+}
+
+// ImportSection итог по разделу: id к добавлению и сколько уже было в аккаунте.
+type ImportSection struct {
+	Added    []string
+	Existing int
+}
+
+// PlaylistImport итог по плейлисту архива.
+type PlaylistImport struct {
+	Title  string
+	Tracks int
+	// Existing у пользователя уже есть плейлист с таким названием — он не трогается.
+	Existing bool
+	// Kind созданного плейлиста (пусто при DryRun и Existing).
+	Kind string
+}
+
+// Import применяет архив к текущему аккаунту: ставит недостающие лайки и дизлайки
+// (от старых к новым, чтобы сохранить порядок библиотеки) и создаёт плейлисты,
+// которых нет по названию. Уже существующие элементы не трогаются, поэтому
+// повторный Import безопасен. При ошибке возвращается отчёт о сделанном до неё.
+func (s *LibraryService) Import(ctx context.Context, a *library.Archive, opts ImportOptions) (*ImportReport, error) {
+	uid := s.c.UID()
+	if uid == "" {
+		return nil, fmt.Errorf("user uid not set; call Account.Status first")
+	}
+	rep := &ImportReport{DryRun: opts.DryRun}
+
+	liked, err := s.LikedTracks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	disliked, err := s.DislikedTracks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	albums, err := s.LikedAlbums(ctx)
+	if err != nil {
+		return nil, err
+	}
+	artists, err := s.LikedArtists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pls, err := s.LikedPlaylists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	have := func(ids ...string) map[string]bool {
+		m := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			m[id] = true
+		}
+		return m
+	}
+	var ids []string
+	for _, lt := range libraryTracks(liked.Result.Library) {
+		ids = append(ids, lt.ID)
+	}
+	rep.LikedTracks = missingTracks(a.LikedTracks, have(ids...))
+	ids = ids[:0]
+	for _, lt := range libraryTracks(disliked.Result.Library) {
+		ids = append(ids, lt.ID)
+	}
+	rep.DislikedTracks = missingTracks(a.DislikedTracks, have(ids...))
+	ids = ids[:0]
+	for _, la := range albums.Result {
+		ids = append(ids, la.ID)
+	}
+	rep.LikedAlbums = missingEntities(a.LikedAlbums, have(ids...))
+	ids = ids[:0]
+	for _, ar := range artists.Result {
+		ids = append(ids, ar.ID)
+	}
+	rep.LikedArtists = missingEntities(a.LikedArtists, have(ids...))
+	ids = ids[:0]
+	for _, lp := range pls.Result {
+		ids = append(ids, playlistKey(lp.Playlist))
+	}
+	rep.LikedPlaylists = missingEntities(a.LikedPlaylists, have(ids...))
+
+	var existing map[string]bool
+	if !opts.SkipPlaylists {
+		favs, err := s.c.Playlist.Favorites(ctx, uid)
+		if err != nil {
+			return nil, err
+		}
+		existing = map[string]bool{}
+		for _, pl := range favs.Result {
+			existing[pl.Title] = true
+		}
+		for _, ap := range a.Playlists {
+			rep.Playlists = append(rep.Playlists, PlaylistImport{Title: ap.Title, Tracks: len(ap.Tracks), Existing: existing[ap.Title]})
+		}
+	}
+	if opts.DryRun {
+		return rep, nil
+	}
+
+	steps := []struct {
+		sec   library.Section
+		typ   library.SectionType
+		added []string
+	}{
+		{library.SectionTracks, library.SectionTypeLikes, rep.LikedTracks.Added},
+		{library.SectionTracks, library.SectionTypeDislikes, rep.DislikedTracks.Added},
+		{library.SectionAlbums, library.SectionTypeLikes, rep.LikedAlbums.Added},
+		{library.SectionArtists, library.SectionTypeLikes, rep.LikedArtists.Added},
+		{library.SectionPlaylists, library.SectionTypeLikes, rep.LikedPlaylists.Added},
+	}
+	for _, st := range steps {
+		if len(st.added) == 0 {
+			continue
+		}
+		res, err := s.bulkModify(ctx, st.sec, st.typ, true, st.added)
+		if err != nil {
+			return rep, err
+		}
+		if st.sec == library.SectionTracks && st.typ == library.SectionTypeLikes {
+			rep.Revision = res.Revision
+		}
+	}
+	for i, ap := range a.Playlists {
+		if opts.SkipPlaylists || rep.Playlists[i].Existing {
+			continue
+		}
+		created, err := s.c.Playlist.Create(ctx, uid, ap.Title)
+		if err != nil {
+			return rep, fmt.Errorf("import playlist %q: %w", ap.Title, err)
+		}
+		pl := created.Result
+		if pl.Owner == nil {
+			pl.Owner = &playlist.Owner{Uid: uid}
+		}
+		rep.Playlists[i].Kind = pl.Kind
+		if len(ap.Tracks) == 0 {
+			continue
+		}
+		keys := make([]playlist.TrackKey, len(ap.Tracks))
+		for j, t := range ap.Tracks {
+			keys[j] = playlist.TrackKey{Id: t.ID, AlbumId: t.AlbumID}
+		}
+		if _, err := s.c.Playlist.InsertTracks(ctx, &pl, keys); err != nil {
+			return rep, fmt.Errorf("import playlist %q: %w", ap.Title, err)
+		}
+	}
+	return rep, nil
+}
+
+// missingTracks ключи треков архива, которых нет в have, от старых к новым.
+func missingTracks(tracks []library.ArchiveTrack, have map[string]bool) ImportSection {
+	var out ImportSection
+	for i := len(tracks) - 1; i >= 0; i-- {
+		t := tracks[i]
+		if have[t.ID] {
+			out.Existing++
+			continue
+		}
+		out.Added = append(out.Added, t.Key())
+	}
+	return out
+}
+
+// missingEntities id архива, которых нет в have, от старых к новым.
+func missingEntities(items []library.ArchiveEntity, have map[string]bool) ImportSection {
+	var out ImportSection
+	for i := len(items) - 1; i >= 0; i-- {
+		e := items[i]
+		if have[e.ID] {
+			out.Existing++
+			continue
+		}
+		out.Added = append(out.Added, e.ID)
+	}
+	return out
+}
+
+// libraryTracks треки библиотеки (nil-безопасно).
+func libraryTracks(lib *library.Library) []library.LibraryTrack {
+	if lib == nil {
+		return nil
+	}
+	return lib.Tracks
+}
@@ -0,0 +1,148 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/library"
+)
+
+func TestLibraryExport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/7/likes/tracks":
+			_, _ = w.Write([]byte(`{"result":{"library":{"revision":3,"tracks":[{"id":"1","albumId":"10","timestamp":"2024-02-01T00:00:00Z"},{"id":"2","albumId":"20","timestamp":"2024-01-01T00:00:00Z"}]}}}`))
+		case "/users/7/dislikes/tracks":
+			_, _ = w.Write([]byte(`{"result":{"library":{"tracks":[{"id":"4"}]}}}`))
+		case "/users/7/playlists/list":
+			_, _ = w.Write([]byte(`{"result":[{"kind":"1000","title":"Mix"}]}`))
+		case "/users/7/playlists/1000":
+			_, _ = w.Write([]byte(`{"result":{"kind":"1000","title":"Mix","description":"d","tracks":[{"id":"2","track":{"id":"2","albums":[{"id":"20"}]}},{"id":"3"}]}}`))
+		case "/tracks":
+			_, _ = w.Write([]byte(`{"result":[
+				{"id":"1","title":"One","durationMs":1000,"artists":[{"name":"A"},{"name":"B"}],"albums":[{"id":"10","title":"Alb"}]},
+				{"id":"2","title":"Two","durationMs":2000},
+				{"id":"3","title":"Three"}]}`))
+		case "/users/7/likes/albums":
+			_, _ = w.Write([]byte(`{"result":[{"id":"10","timestamp":"2024-01-01T00:00:00Z"}]}`))
+		case "/albums":
+			_, _ = w.Write([]byte(`{"result":[{"id":"10","title":"Alb"}]}`))
+		case "/users/7/likes/artists":
+			_, _ = w.Write([]byte(`{"result":[{"artist":{"id":"5","name":"A"},"timestamp":"2024-01-01T00:00:00Z"}]}`))
+		case "/users/7/likes/playlists":
+			_, _ = w.Write([]byte(`{"result":[{"playlist":{"owner":{"uid":"9"},"kind":"3","title":"Road"}}]}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	st := auth.New("tok")
+	st.SetUid("7")
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithAuthStorage(st))
+	a, err := c.Library.Export(context.Background())
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if a.Version != library.ArchiveVersion || a.UID != "7" || len(a.LikedTracks) != 2 || len(a.DislikedTracks) != 1 {
+		t.Fatalf("archive %+v", a)
+	}
+	if lt := a.LikedTracks[0]; lt.Title != "One" || lt.Album != "Alb" || lt.DurationMs != 1000 || strings.Join(lt.Artists, ",") != "A,B" {
+		t.Fatalf("track metadata %+v", lt)
+	}
+	if len(a.Playlists) != 1 || len(a.Playlists[0].Tracks) != 2 || a.Playlists[0].Tracks[0].AlbumID != "20" || a.Playlists[0].Tracks[1].Title != "Three" {
+		t.Fatalf("playlists %+v", a.Playlists)
+	}
+	if a.LikedAlbums[0].Title != "Alb" || a.LikedArtists[0].Title != "A" || a.LikedPlaylists[0].ID != "9:3" {
+		t.Fatalf("entities %+v %+v %+v", a.LikedAlbums, a.LikedArtists, a.LikedPlaylists)
+	}
+	var buf bytes.Buffer
+	if err := a.WriteCSV(&buf); err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	if _, err := library.ReadArchive(&buf); err != nil {
+		t.Fatalf("read back: %v", err)
+	}
+}
+
+func TestLibraryImport(t *testing.T) {
+	var mu sync.Mutex
+	posts := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPost {
+			posts[r.URL.Path] = r.PostForm.Encode()
+		}
+		switch r.URL.Path {
+		case "/users/8/likes/tracks":
+			_, _ = w.Write([]byte(`{"result":{"library":{"tracks":[{"id":"2"}]}}}`))
+		case "/users/8/dislikes/tracks":
+			_, _ = w.Write([]byte(`{"result":{"library":{}}}`))
+		case "/users/8/likes/albums", "/users/8/likes/artists", "/users/8/likes/playlists":
+			_, _ = w.Write([]byte(`{"result":[]}`))
+		case "/users/8/playlists/list":
+			_, _ = w.Write([]byte(`{"result":[{"kind":"5","title":"Old"}]}`))
+		case "/users/8/likes/tracks/add-multiple":
+			_, _ = w.Write([]byte(`{"result":{"revision":21}}`))
+		case "/users/8/likes/albums/add-multiple":
+			_, _ = w.Write([]byte(`{"result":"ok"}`))
+		case "/users/8/playlists/create":
+			_, _ = w.Write([]byte(`{"result":{"kind":"77","title":"Mix","revision":1}}`))
+		case "/users/8/playlists/77/change":
+			_, _ = w.Write([]byte(`{"result":{"kind":"77","revision":2}}`))
+		case "/users/8/playlists/77":
+			_, _ = w.Write([]byte(`{"result":{"kind":"77","revision":2}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	st := auth.New("tok")
+	st.SetUid("8")
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithAuthStorage(st))
+	a := &library.Archive{
+		Version:     library.ArchiveVersion,
+		LikedTracks: []library.ArchiveTrack{{ID: "3", AlbumID: "30"}, {ID: "2"}, {ID: "1", AlbumID: "10"}},
+		LikedAlbums: []library.ArchiveEntity{{ID: "10"}},
+		Playlists:   []library.ArchivePlaylist{{Kind: "1000", Title: "Mix", Tracks: []library.ArchiveTrack{{ID: "1", AlbumID: "10"}}}, {Title: "Old"}},
+	}
+
+	rep, err := c.Library.Import(context.Background(), a, sdk.ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(posts) != 0 {
+		t.Fatalf("dry run must not mutate: %v", posts)
+	}
+	if strings.Join(rep.LikedTracks.Added, ",") != "1:10,3:30" || rep.LikedTracks.Existing != 1 {
+		t.Fatalf("liked tracks report %+v", rep.LikedTracks)
+	}
+	if len(rep.Playlists) != 2 || rep.Playlists[0].Existing || !rep.Playlists[1].Existing {
+		t.Fatalf("playlists report %+v", rep.Playlists)
+	}
+
+	rep, err = c.Library.Import(context.Background(), a, sdk.ImportOptions{})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if rep.Revision != 21 || rep.Playlists[0].Kind != "77" {
+		t.Fatalf("report %+v", rep)
+	}
+	if got := posts["/users/8/likes/tracks/add-multiple"]; got != "track-ids=1%3A10%2C3%3A30" {
+		t.Fatalf("likes form %q", got)
+	}
+	if got := posts["/users/8/playlists/77/change"]; !strings.Contains(got, "revision=1") || !strings.Contains(got, "%22id%22%3A%221%22") {
+		t.Fatalf("change form %q", got)
+	}
+	if _, ok := posts["/users/8/likes/albums/add-multiple"]; !ok {
+		t.Fatalf("album likes not imported: %v", posts)
+	}
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Banjirome/yandex-music-go/library"
+	"github.com/Banjirome/yandex-music-go/models"
+)
+
+// BulkResult итог пакетного изменения лайков/дизлайков.
+type BulkResult struct {
+	// Revision ревизия после последнего чанка (0, если раздел её не возвращает —
+	// альбомы, исполнители и плейлисты отвечают "ok").
+	Revision int
+	// Done сколько id обработано; при ошибке — успешно отправленные до неё.
+	Done int
+}
+
+// AddLikes лайкает ids раздела (треки "id" или "id:albumId", альбомы, исполнители,
+// плейлисты "uid:kind") чанками по Config.BatchSize.
+func (s *LibraryService) AddLikes(ctx context.Context, section library.Section, ids ...string) (*BulkResult, error) {
+	return s.bulkModify(ctx, section, library.SectionTypeLikes, true, ids)
+}
+
+// RemoveLikes снимает лайки с ids раздела чанками по Config.BatchSize.
+func (s *LibraryService) RemoveLikes(ctx context.Context, section library.Section, ids ...string) (*BulkResult, error) {
+	return s.bulkModify(ctx, section, library.SectionTypeLikes, false, ids)
+}
+
+// AddDislikes дизлайкает ids раздела (поддерживаются треки и исполнители).
+func (s *LibraryService) AddDislikes(ctx context.Context, section library.Section, ids ...string) (*BulkResult, error) {
+	return s.bulkModify(ctx, section, library.SectionTypeDislikes, true, ids)
+}
+
+// RemoveDislikes снимает дизлайки с ids раздела.
+func (s *LibraryService) RemoveDislikes(ctx context.Context, section library.Section, ids ...string) (*BulkResult, error) {
+	return s.bulkModify(ctx, section, library.SectionTypeDislikes, false, ids)
+}
+
+// bulkModify отправляет чанки последовательно: каждый меняет ревизию библиотеки,
+// поэтому параллельность здесь не нужна.
+func (s *LibraryService) bulkModify(ctx context.Context, section library.Section, typ library.SectionType, add bool, ids []string) (*BulkResult, error) {
+	size := s.c.cfg.BatchSize
+	if size <= 0 {
+		size = defaultBatchSize
+	}
+	out := &BulkResult{}
+	for start := 0; start < len(ids); start += size {
+		chunk := ids[start:min(start+size, len(ids))]
+		rev, err := s.modifyChunk(ctx, section, typ, add, chunk)
+		if err != nil {
+			return out, fmt.Errorf("bulk %s %s: after %d of %d ids: %w", typ, section, out.Done, len(ids), err)
+		}
+		if rev > 0 {
+			out.Revision = rev
+		}
+		out.Done += len(chunk)
+	}
+	return out, nil
+}
+
+func (s *LibraryService) modifyChunk(ctx context.Context, section library.Section, typ library.SectionType, add bool, chunk []string) (int, error) {
+	resp, err := s.modifyForm(ctx, section, typ, add, strings.Join(chunk, ","))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return 0, fmt.Errorf("modify failed: %s", resp.Status)
+	}
+	var out models.Response[json.RawMessage]
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return 0, err
+	}
+	// треки отвечают {"revision":N}, остальные разделы — строкой "ok"
+	var rev models.Revision
+	if len(out.Result) > 0 && out.Result[0] == '{' {
+		if err := json.Unmarshal(out.Result, &rev); err != nil {
+			return 0, err
+		}
+	}
+	return rev.Revision, nil
+}
@@ -0,0 +1,75 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/library"
+)
+
+func TestBulkLikesChunking(t *testing.T) {
+	var chunks []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		switch r.URL.Path {
+		case "/users/7/likes/tracks/add-multiple":
+			chunks = append(chunks, r.PostForm.Get("track-ids"))
+			fmt.Fprintf(w, `{"result":{"revision":%d}}`, 10+len(chunks))
+		case "/users/7/dislikes/artists/remove":
+			if ids := r.PostForm.Get("artist-ids"); ids != "1,2" {
+				t.Errorf("artist ids %q", ids)
+			}
+			_, _ = w.Write([]byte(`{"result":"ok"}`))
+		default:
+			t.Errorf("unexpected %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	st := auth.New("tok")
+	st.SetUid("7")
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithAuthStorage(st), sdk.WithBatching(2, 4))
+
+	res, err := c.Library.AddLikes(context.Background(), library.SectionTracks, "1:10", "2", "3", "4", "5")
+	if err != nil {
+		t.Fatalf("add likes: %v", err)
+	}
+	if res.Revision != 13 || res.Done != 5 {
+		t.Fatalf("result %+v", res)
+	}
+	if strings.Join(chunks, "|") != "1:10,2|3,4|5" {
+		t.Fatalf("chunks %v", chunks)
+	}
+	res, err = c.Library.RemoveDislikes(context.Background(), library.SectionArtists, "1", "2")
+	if err != nil || res.Revision != 0 || res.Done != 2 {
+		t.Fatalf("remove dislikes: %v %+v", err, res)
+	}
+}
+
+func TestBulkLikesPartialFailure(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":{"revision":4}}`))
+	}))
+	defer srv.Close()
+	st := auth.New("tok")
+	st.SetUid("7")
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithAuthStorage(st), sdk.WithBatching(1, 1))
+	res, err := c.Library.RemoveLikes(context.Background(), library.SectionTracks, "1", "2", "3")
+	if err == nil || res == nil || res.Done != 1 || res.Revision != 4 {
+		t.Fatalf("expected partial result, got %+v %v", res, err)
+	}
+	if calls != 2 {
+		t.Fatalf("must stop after failure, calls=%d", calls)
+	}
+}
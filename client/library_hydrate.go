@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/library"
+)
+
+// LikedTrack трек из «Мне нравится» с полными метаданными и временем лайка.
+type LikedTrack struct {
+	Track     Track
+	AlbumID   string
+	Timestamp time.Time
+}
+
+// LikedTracksResult результат LikedTracksFull.
+type LikedTracksResult struct {
+	// Tracks доступные треки в порядке библиотеки.
+	Tracks []LikedTrack
+	// Unavailable треки, изъятые из каталога или не вернувшиеся из tracks
+	// (у ненайденных заполнен только Track.ID).
+	Unavailable []LikedTrack
+	// Revision ревизия библиотеки лайков.
+	Revision int
+}
+
+// LikedTracksFull получает лайкнутые треки с полными метаданными: LikedTracks + Track.GetReport
+// (чанки и параллельность по WithBatching). Порядок и время лайков сохраняются.
+func (s *LibraryService) LikedTracksFull(ctx context.Context) (*LikedTracksResult, error) {
+	liked, err := s.LikedTracks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := &LikedTracksResult{}
+	lib := liked.Result.Library
+	if lib == nil || len(lib.Tracks) == 0 {
+		return out, nil
+	}
+	out.Revision = lib.Revision
+	keys := make([]string, len(lib.Tracks))
+	for i, lt := range lib.Tracks {
+		keys[i] = libraryTrackKey(lt)
+	}
+	res, err := s.c.Track.GetReport(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]Track, len(res.Items))
+	for _, tr := range res.Items {
+		byID[tr.ID] = tr
+	}
+	for _, lt := range lib.Tracks {
+		item := LikedTrack{AlbumID: lt.AlbumID, Timestamp: lt.Timestamp}
+		tr, ok := byID[lt.ID]
+		if !ok {
+			item.Track = Track{ID: lt.ID}
+			out.Unavailable = append(out.Unavailable, item)
+			continue
+		}
+		item.Track = tr
+		if !tr.IsAvailable() {
+			out.Unavailable = append(out.Unavailable, item)
+			continue
+		}
+		out.Tracks = append(out.Tracks, item)
+	}
+	return out, nil
+}
+
+// libraryTrackKey ключ трека "id:albumId" (или просто id без альбома).
+func libraryTrackKey(lt library.LibraryTrack) string {
+	if lt.AlbumID == "" {
+		return lt.ID
+	}
+	return lt.ID + ":" + lt.AlbumID
+}
@@ -0,0 +1,55 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+	sdk "github.com/Banjirome/yandex-music-go/client"
+)
+
+func TestLikedTracksFull(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/7/likes/tracks":
+			_, _ = w.Write([]byte(`{"result":{"library":{"uid":"7","revision":12,"tracks":[
+				{"id":"3","albumId":"30","timestamp":"2024-03-01T00:00:00Z"},
+				{"id":"2","albumId":"20","timestamp":"2024-02-01T00:00:00Z"},
+				{"id":"1","albumId":"10","timestamp":"2024-01-01T00:00:00Z"},
+				{"id":"9","timestamp":"2023-12-01T00:00:00Z"}]}}}`))
+		case "/tracks":
+			_ = r.ParseForm()
+			if ids := r.PostForm.Get("track-ids"); !strings.HasPrefix(ids, "3:30,2:20") && !strings.HasPrefix(ids, "1:10") {
+				t.Errorf("unexpected chunk %q", ids)
+			}
+			// 9 не возвращается, 2 изъят
+			_, _ = w.Write([]byte(`{"result":[{"id":"1","title":"one"},{"id":"2","title":"two","available":false},{"id":"3","title":"three","available":true}]}`))
+		default:
+			t.Errorf("unexpected %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	st := auth.New("tok")
+	st.SetUid("7")
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithAuthStorage(st), sdk.WithBatching(2, 2))
+	res, err := c.Library.LikedTracksFull(context.Background())
+	if err != nil {
+		t.Fatalf("hydrate: %v", err)
+	}
+	if res.Revision != 12 || len(res.Tracks) != 2 {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	if res.Tracks[0].Track.Title != "three" || res.Tracks[1].Track.Title != "one" {
+		t.Fatalf("order lost: %+v", res.Tracks)
+	}
+	if !res.Tracks[0].Timestamp.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) || res.Tracks[0].AlbumID != "30" {
+		t.Fatalf("like metadata lost: %+v", res.Tracks[0])
+	}
+	if len(res.Unavailable) != 2 || res.Unavailable[0].Track.Title != "two" || res.Unavailable[1].Track.ID != "9" {
+		t.Fatalf("unavailable %+v", res.Unavailable)
+	}
+}
@@ -4,12 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
 
+	"github.com/Banjirome/yandex-music-go/artist"
 	"github.com/Banjirome/yandex-music-go/library"
 	"github.com/Banjirome/yandex-music-go/models"
 	"github.com/Banjirome/yandex-music-go/playlist"
@@ -20,41 +20,55 @@ type LibraryService struct{ c *Client }
 
 // internal helper building path: users/{uid}/{type}/{section}
 func (s *LibraryService) sectionPath(section library.Section, typ library.SectionType) (string, error) {
-	if s.c.auth == nil || s.c.auth.User == nil || s.c.auth.User.Uid == "" {
+	uid := s.c.UID()
+	if uid == "" {
 		return "", fmt.Errorf("user uid not set; call Account.Status first")
 	}
-	return path.Join("users", s.c.auth.User.Uid, string(typ), string(section)), nil
+	return path.Join("users", uid, string(typ), string(section)), nil
 }
 
 // generic GET for sections
-func getSection[T any](s *LibraryService, ctx context.Context, section library.Section, typ library.SectionType) (*models.Response[T], error) {
+func getSection[T any](s *LibraryService, ctx context.Context, section library.Section, typ library.SectionType, q url.Values) (*models.Response[T], error) {
 	p, err := s.sectionPath(section, typ)
 	if err != nil {
 		return nil, err
 	}
-	return doJSON[T](s.c, ctx, http.MethodGet, p, nil, nil)
+	return doJSON[T](s.c, ctx, http.MethodGet, p, q, nil)
 }
 
+// withTimestamps query для вариантов, где сервер оборачивает объекты в {объект, timestamp}.
+var withTimestamps = url.Values{"with-timestamps": {"true"}}
+
 // Liked entities
 func (s *LibraryService) LikedTracks(ctx context.Context) (*models.Response[library.LibraryTracks], error) {
-	return getSection[library.LibraryTracks](s, ctx, library.SectionTracks, library.SectionTypeLikes)
+	return getSection[library.LibraryTracks](s, ctx, library.SectionTracks, library.SectionTypeLikes, nil)
 }
 func (s *LibraryService) LikedAlbums(ctx context.Context) (*models.Response[[]library.LibraryAlbum], error) {
-	return getSection[[]library.LibraryAlbum](s, ctx, library.SectionAlbums, library.SectionTypeLikes)
+	return getSection[[]library.LibraryAlbum](s, ctx, library.SectionAlbums, library.SectionTypeLikes, nil)
+}
+func (s *LibraryService) LikedArtists(ctx context.Context) (*models.Response[[]artist.Artist], error) {
+	return getSection[[]artist.Artist](s, ctx, library.SectionArtists, library.SectionTypeLikes, nil)
 }
-func (s *LibraryService) LikedArtists(ctx context.Context) (*models.Response[[]any], error) { // TODO: implement full artist model ref if needed
-	return getSection[[]any](s, ctx, library.SectionArtists, library.SectionTypeLikes)
+
+// LikedArtistsWithTimestamps лайкнутые исполнители со временем лайка.
+func (s *LibraryService) LikedArtistsWithTimestamps(ctx context.Context) (*models.Response[[]library.LibraryArtist], error) {
+	return getSection[[]library.LibraryArtist](s, ctx, library.SectionArtists, library.SectionTypeLikes, withTimestamps)
 }
 func (s *LibraryService) LikedPlaylists(ctx context.Context) (*models.Response[[]library.LibraryPlaylists], error) {
-	return getSection[[]library.LibraryPlaylists](s, ctx, library.SectionPlaylists, library.SectionTypeLikes)
+	return getSection[[]library.LibraryPlaylists](s, ctx, library.SectionPlaylists, library.SectionTypeLikes, nil)
 }
 
 // Disliked entities
 func (s *LibraryService) DislikedTracks(ctx context.Context) (*models.Response[library.LibraryTracks], error) {
-	return getSection[library.LibraryTracks](s, ctx, library.SectionTracks, library.SectionTypeDislikes)
+	return getSection[library.LibraryTracks](s, ctx, library.SectionTracks, library.SectionTypeDislikes, nil)
 }
-func (s *LibraryService) DislikedArtists(ctx context.Context) (*models.Response[[]any], error) {
-	return getSection[[]any](s, ctx, library.SectionArtists, library.SectionTypeDislikes)
+func (s *LibraryService) DislikedArtists(ctx context.Context) (*models.Response[[]artist.Artist], error) {
+	return getSection[[]artist.Artist](s, ctx, library.SectionArtists, library.SectionTypeDislikes, nil)
+}
+
+// DislikedArtistsWithTimestamps дизлайкнутые исполнители со временем дизлайка.
+func (s *LibraryService) DislikedArtistsWithTimestamps(ctx context.Context) (*models.Response[[]library.LibraryArtist], error) {
+	return getSection[[]library.LibraryArtist](s, ctx, library.SectionArtists, library.SectionTypeDislikes, withTimestamps)
 }
 
 // modify helper: POST users/{uid}/{type}/{section}/add-multiple or /remove with form field <singular>-ids
@@ -71,14 +85,11 @@ func (s *LibraryService) modifyForm(ctx context.Context, section library.Section
 	form := url.Values{}
 	singular := strings.TrimSuffix(string(section), "s")
 	form.Set(singular+"-ids", id)
-	req, err := s.c.newRequest(ctx, http.MethodPost, p, nil, nil)
+	req, err := s.c.newRequest(ctx, http.MethodPost, p, nil, form)
 	if err != nil {
 		return nil, err
 	}
-	encoded := form.Encode()
-	req.Body = io.NopCloser(strings.NewReader(encoded))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	return s.c.http.Do(req)
+	return s.c.do(req)
 }
 
 // Likes/dislikes operations
@@ -236,10 +247,11 @@ func (s *LibraryService) RemovePlaylistLike(ctx context.Context, playlistKey str
 
 // RecentlyListened mirrors GetRecentlyListenedAsync
 func (s *LibraryService) RecentlyListened(ctx context.Context, types []library.PlayContextType, trackCount, contextCount int) (*models.Response[library.RecentlyListenedContext], error) {
-	if s.c.auth == nil || s.c.auth.User == nil || s.c.auth.User.Uid == "" {
+	uid := s.c.UID()
+	if uid == "" {
 		return nil, fmt.Errorf("user uid not set")
 	}
-	p := path.Join("users", s.c.auth.User.Uid, "contexts")
+	p := path.Join("users", uid, "contexts")
 	q := url.Values{}
 	q.Set("trackCount", fmt.Sprintf("%d", trackCount))
 	q.Set("contextCount", fmt.Sprintf("%d", contextCount))
@@ -253,14 +265,11 @@ func (s *LibraryService) RecentlyListened(ctx context.Context, types []library.P
 
 // doForm is like doJSON but sends form data.
 func doForm[T any](c *Client, ctx context.Context, p string, form url.Values) (*models.Response[T], error) {
-	req, err := c.newRequest(ctx, http.MethodPost, p, nil, nil)
+	req, err := c.newRequest(ctx, http.MethodPost, p, nil, form)
 	if err != nil {
 		return nil, err
 	}
-	encoded := form.Encode()
-	req.Body = io.NopCloser(strings.NewReader(encoded))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := c.http.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
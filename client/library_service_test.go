@@ -0,0 +1,60 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+	sdk "github.com/Banjirome/yandex-music-go/client"
+)
+
+func TestLikedArtistsAndPlaylistsTyped(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/7/likes/artists":
+			if r.URL.Query().Get("with-timestamps") == "true" {
+				_, _ = w.Write([]byte(`{"result":[{"artist":{"id":"1","name":"Kino"},"timestamp":"2024-05-01T10:00:00Z"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"result":[{"id":1,"name":"Kino"}]}`))
+		case "/users/7/dislikes/artists":
+			// API отдаёт id исполнителя числом
+			_, _ = w.Write([]byte(`{"result":[{"artist":{"id":2,"name":"X"},"timestamp":"2023-01-01T00:00:00Z"}]}`))
+		case "/users/7/likes/playlists":
+			_, _ = w.Write([]byte(`{"result":[{"playlist":{"uid":"9","kind":"3","title":"Road","trackCount":12},"timestamp":"2024-02-01T00:00:00Z"}]}`))
+		default:
+			t.Errorf("unexpected %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	st := auth.New("tok")
+	st.SetUid("7")
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithAuthStorage(st))
+	ctx := context.Background()
+
+	artists, err := c.Library.LikedArtists(ctx)
+	if err != nil || len(artists.Result) != 1 || artists.Result[0].Name != "Kino" || artists.Result[0].ID != "1" {
+		t.Fatalf("liked artists: %v %+v", err, artists)
+	}
+	stamped, err := c.Library.LikedArtistsWithTimestamps(ctx)
+	if err != nil || len(stamped.Result) != 1 {
+		t.Fatalf("liked artists with timestamps: %v %+v", err, stamped)
+	}
+	if la := stamped.Result[0]; la.Artist.ID != "1" || la.Timestamp.Year() != 2024 {
+		t.Fatalf("unexpected %+v", la)
+	}
+	disliked, err := c.Library.DislikedArtistsWithTimestamps(ctx)
+	if err != nil || disliked.Result[0].Artist.Name != "X" || disliked.Result[0].Artist.ID != "2" {
+		t.Fatalf("disliked artists: %v %+v", err, disliked)
+	}
+	pls, err := c.Library.LikedPlaylists(ctx)
+	if err != nil || len(pls.Result) != 1 {
+		t.Fatalf("liked playlists: %v %+v", err, pls)
+	}
+	if lp := pls.Result[0]; lp.Playlist.Title != "Road" || lp.Playlist.TrackCount != 12 || !lp.Timestamp.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected playlist %+v", lp)
+	}
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/library"
+)
+
+// LibrarySync инкрементальная синхронизация лайков с локальным снимком.
+//
+// Лайкнутые треки запрашиваются с if-modified-since-revision и не сравниваются,
+// пока ревизия не изменилась, так что Run без изменений стоит одного запроса.
+// У альбомов, исполнителей и плейлистов ревизии нет: они синхронизируются, только
+// если перечислены в AlsoSync, и тогда запрашиваются при каждом Run.
+type LibrarySync struct {
+	s     *LibraryService
+	store library.SyncStore
+	// AlsoSync разделы без ревизии (SectionAlbums, SectionArtists, SectionPlaylists),
+	// которые сравниваются при каждом Run ценой отдельного запроса на раздел.
+	// Элементы остальных разделов переносятся из прежнего снимка без изменений.
+	AlsoSync []library.Section
+	// Handler вызывается для каждого события до сохранения снимка.
+	// Ошибка прерывает Run, снимок при этом не сохраняется (события придут повторно).
+	Handler func(library.Event) error
+}
+
+// NewSync создаёт синхронизатор лайков поверх store.
+func (s *LibraryService) NewSync(store library.SyncStore) *LibrarySync {
+	return &LibrarySync{s: s, store: store}
+}
+
+// Run сравнивает текущие лайки со снимком, вызывает Handler и сохраняет новый снимок.
+// Первый запуск (снимка нет) возвращает все элементы как EventAdded.
+func (ls *LibrarySync) Run(ctx context.Context) ([]library.Event, error) {
+	uid := ls.s.c.UID()
+	if uid == "" {
+		return nil, fmt.Errorf("user uid not set; call Account.Status first")
+	}
+	prev, err := ls.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	if prev != nil && prev.UID != uid {
+		return nil, fmt.Errorf("library sync: snapshot belongs to uid %s", prev.UID)
+	}
+	if prev == nil {
+		prev = &library.Snapshot{UID: uid, Revision: -1}
+	}
+	cur := &library.Snapshot{UID: uid, Items: map[library.Section][]library.Item{}}
+
+	if cur.Revision, cur.Items[library.SectionTracks], err = ls.tracks(ctx, prev); err != nil {
+		return nil, err
+	}
+	fetch := map[library.Section]func(context.Context) ([]library.Item, error){
+		library.SectionAlbums:    ls.albums,
+		library.SectionArtists:   ls.artists,
+		library.SectionPlaylists: ls.playlists,
+	}
+	for sec, items := range prev.Items {
+		if sec != library.SectionTracks {
+			cur.Items[sec] = items
+		}
+	}
+	for _, sec := range ls.AlsoSync {
+		f, ok := fetch[sec]
+		if !ok {
+			return nil, fmt.Errorf("library sync: unsupported section %q", sec)
+		}
+		if cur.Items[sec], err = f(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var events []library.Event
+	for _, sec := range []library.Section{library.SectionTracks, library.SectionAlbums, library.SectionArtists, library.SectionPlaylists} {
+		events = append(events, library.Diff(sec, prev.Items[sec], cur.Items[sec])...)
+	}
+	if ls.Handler != nil {
+		for _, ev := range events {
+			if err := ls.Handler(ev); err != nil {
+				return nil, err
+			}
+		}
+	}
+	cur.SyncedAt = time.Now()
+	if err := ls.store.Save(cur); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// tracks возвращает ревизию и список треков; при неизменной ревизии — из снимка.
+func (ls *LibrarySync) tracks(ctx context.Context, prev *library.Snapshot) (int, []library.Item, error) {
+	p, err := ls.s.sectionPath(library.SectionTracks, library.SectionTypeLikes)
+	if err != nil {
+		return 0, nil, err
+	}
+	var q url.Values
+	if prev.Revision >= 0 {
+		q = url.Values{"if-modified-since-revision": {strconv.Itoa(prev.Revision)}}
+	}
+	resp, err := doJSON[library.LibraryTracks](ls.s.c, ctx, http.MethodGet, p, q, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	lib := resp.Result.Library
+	if lib == nil || lib.Revision == prev.Revision {
+		return prev.Revision, prev.Items[library.SectionTracks], nil
+	}
+	items := make([]library.Item, len(lib.Tracks))
+	for i, lt := range lib.Tracks {
+		items[i] = library.Item{ID: lt.ID, AlbumID: lt.AlbumID, Timestamp: lt.Timestamp}
+	}
+	return lib.Revision, items, nil
+}
+
+func (ls *LibrarySync) albums(ctx context.Context) ([]library.Item, error) {
+	resp, err := ls.s.LikedAlbums(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]library.Item, len(resp.Result))
+	for i, a := range resp.Result {
+		items[i] = library.Item{ID: a.ID, Timestamp: a.Timestamp}
+	}
+	return items, nil
+}
+
+func (ls *LibrarySync) artists(ctx context.Context) ([]library.Item, error) {
+	resp, err := ls.s.LikedArtistsWithTimestamps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]library.Item, len(resp.Result))
+	for i, la := range resp.Result {
+		items[i] = library.Item{ID: la.Artist.ID, Timestamp: la.Timestamp}
+	}
+	return items, nil
+}
+
+func (ls *LibrarySync) playlists(ctx context.Context) ([]library.Item, error) {
+	resp, err := ls.s.LikedPlaylists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]library.Item, len(resp.Result))
+	for i, lp := range resp.Result {
+		items[i] = library.Item{ID: playlistKey(lp.Playlist), Timestamp: lp.Timestamp}
+	}
+	return items, nil
+}
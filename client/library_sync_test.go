@@ -0,0 +1,165 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/library"
+)
+
+func TestLibrarySyncIncremental(t *testing.T) {
+	var mu sync.Mutex
+	rev := 5
+	tracks := `{"id":"1","albumId":"10"},{"id":"2","albumId":"20"}`
+	albums := `[{"id":"100"}]`
+	var sinceSeen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/users/7/likes/tracks":
+			since := r.URL.Query().Get("if-modified-since-revision")
+			sinceSeen = append(sinceSeen, since)
+			if since == "5" && rev == 5 {
+				// ревизия не изменилась: сервер отдаёт библиотеку без треков
+				_, _ = w.Write([]byte(`{"result":{"library":{"uid":"7","revision":5}}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"result":{"library":{"uid":"7","revision":` + strconv.Itoa(rev) + `,"tracks":[` + tracks + `]}}}`))
+		case "/users/7/likes/albums":
+			_, _ = w.Write([]byte(`{"result":` + albums + `}`))
+		case "/users/7/likes/artists":
+			_, _ = w.Write([]byte(`{"result":[{"artist":{"id":42,"name":"a"},"timestamp":"2024-01-01T00:00:00Z"}]}`))
+		case "/users/7/likes/playlists":
+			_, _ = w.Write([]byte(`{"result":[{"playlist":{"uid":9,"kind":3}}]}`))
+		default:
+			t.Errorf("unexpected %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	st := auth.New("tok")
+	st.SetUid("7")
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithAuthStorage(st))
+	store := library.NewMemoryStore()
+	ls := c.Library.NewSync(store)
+	ls.AlsoSync = []library.Section{library.SectionAlbums, library.SectionArtists, library.SectionPlaylists}
+
+	events, err := ls.Run(context.Background())
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if len(events) != 5 {
+		t.Fatalf("expected 5 added events, got %+v", events)
+	}
+	if ev := events[3]; ev.Section != library.SectionArtists || ev.Item.ID != "42" {
+		t.Fatalf("artist event %+v", ev)
+	}
+	if ev := events[4]; ev.Section != library.SectionPlaylists || ev.Item.ID != "9:3" {
+		t.Fatalf("playlist event %+v", ev)
+	}
+
+	// без изменений: пустой список треков в ответе не должен давать removed
+	events, err = ls.Run(context.Background())
+	if err != nil || len(events) != 0 {
+		t.Fatalf("second run: %v %+v", err, events)
+	}
+
+	mu.Lock()
+	rev = 6
+	tracks = `{"id":"2","albumId":"20"},{"id":"3","albumId":"30"}`
+	albums = `[]`
+	mu.Unlock()
+	events, err = ls.Run(context.Background())
+	if err != nil {
+		t.Fatalf("third run: %v", err)
+	}
+	want := []string{"added tracks 3", "removed tracks 1", "removed albums 100"}
+	if len(events) != len(want) {
+		t.Fatalf("events %+v", events)
+	}
+	for i, ev := range events {
+		if got := string(ev.Kind) + " " + string(ev.Section) + " " + ev.Item.ID; got != want[i] {
+			t.Fatalf("event %d = %q, want %q", i, got, want[i])
+		}
+	}
+	snap, _ := store.Load()
+	if snap.Revision != 6 || len(snap.Items[library.SectionTracks]) != 2 {
+		t.Fatalf("snapshot %+v", snap)
+	}
+	if sinceSeen[0] != "" || sinceSeen[1] != "5" || sinceSeen[2] != "5" {
+		t.Fatalf("revision param %v", sinceSeen)
+	}
+}
+
+func TestLibrarySyncHandlerErrorKeepsSnapshot(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/7/likes/tracks" {
+			_, _ = w.Write([]byte(`{"result":{"library":{"uid":"7","revision":1,"tracks":[{"id":"1"}]}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":[]}`))
+	}))
+	defer srv.Close()
+	st := auth.New("tok")
+	st.SetUid("7")
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithAuthStorage(st))
+	store := library.NewMemoryStore()
+	ls := c.Library.NewSync(store)
+	boom := errors.New("downstream down")
+	ls.Handler = func(library.Event) error { return boom }
+	if _, err := ls.Run(context.Background()); !errors.Is(err, boom) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+	if snap, _ := store.Load(); snap != nil {
+		t.Fatalf("snapshot must not be saved: %+v", snap)
+	}
+	ls.Handler = nil
+	if events, err := ls.Run(context.Background()); err != nil || len(events) != 1 {
+		t.Fatalf("retry: %v %+v", err, events)
+	}
+}
+
+func TestLibrarySyncUnchangedCostsOneRequest(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path != "/users/7/likes/tracks" {
+			t.Errorf("unexpected %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("if-modified-since-revision") == "2" {
+			_, _ = w.Write([]byte(`{"result":{"library":{"uid":"7","revision":2}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":{"library":{"uid":"7","revision":2,"tracks":[{"id":"1"}]}}}`))
+	}))
+	defer srv.Close()
+	st := auth.New("tok")
+	st.SetUid("7")
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithAuthStorage(st))
+	store := library.NewMemoryStore()
+	// альбомы из прежнего снимка переносятся, а не считаются удалёнными
+	_ = store.Save(&library.Snapshot{UID: "7", Revision: -1, Items: map[library.Section][]library.Item{library.SectionAlbums: {{ID: "100"}}}})
+	ls := c.Library.NewSync(store)
+	if events, err := ls.Run(context.Background()); err != nil || len(events) != 1 {
+		t.Fatalf("first run: %v %+v", err, events)
+	}
+	if events, err := ls.Run(context.Background()); err != nil || len(events) != 0 {
+		t.Fatalf("second run: %v %+v", err, events)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("requests %v", paths)
+	}
+	if snap, _ := store.Load(); len(snap.Items[library.SectionAlbums]) != 1 {
+		t.Fatalf("snapshot %+v", snap)
+	}
+}
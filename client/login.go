@@ -0,0 +1,218 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/models"
+)
+
+// ChallengeHandler отвечает на шаги интерактивного входа UserService.Login.
+type ChallengeHandler interface {
+	// Captcha возвращает ответ на captcha по ссылке на изображение.
+	Captcha(ctx context.Context, imageURL string) (string, error)
+	// Password возвращает пароль (или пароль приложения) для логина.
+	Password(ctx context.Context, login string) (string, error)
+	// ShowQR показывает ссылку (QR) для подтверждения входа с телефона.
+	ShowQR(ctx context.Context, link string) error
+	// ConfirmEmail сообщает, что письмо со ссылкой отправлено; дальше Login ожидает подтверждения.
+	ConfirmEmail(ctx context.Context, login string) error
+}
+
+// MethodChooser опционально реализуется ChallengeHandler для выбора метода входа вместо
+// первого поддерживаемого из preferred_auth_methods.
+type MethodChooser interface {
+	ChooseMethod(ctx context.Context, methods []string) (string, error)
+}
+
+// QRStatusHandler опционально реализуется ChallengeHandler, чтобы получать
+// статус каждого опроса QR-входа (после ShowQR).
+type QRStatusHandler interface {
+	QRStatus(ctx context.Context, st *models.AuthQRStatus)
+}
+
+// Методы входа из preferred_auth_methods, которые поддерживает Login.
+const (
+	AuthMethodPassword  = "password"
+	AuthMethodMagicLink = "magic_link"
+	AuthMethodQR        = "magic_x_token"
+)
+
+// ErrNoAuthMethod возвращается, если сервер не предложил ни одного поддерживаемого метода.
+var ErrNoAuthMethod = errors.New("login: no supported auth method")
+
+type loginStep int
+
+const (
+	loginStart loginStep = iota
+	loginPassword
+	loginCaptcha
+	loginLetter
+	loginQR
+	loginToken
+	loginDone
+)
+
+// параметры ожидания подтверждения (письмо, QR)
+var (
+	loginPollInterval = 2 * time.Second
+	loginConfirmWait  = 3 * time.Minute
+	loginCaptchaTries = 3
+)
+
+// Login проводит многошаговую авторизацию: CreateAuthSession -> выбор метода ->
+// captcha/пароль/QR/письмо -> GetAccessToken -> Authorize.
+// Требует WithClientCredentials и WithXClientCredentials.
+func (s *UserService) Login(ctx context.Context, login string, h ChallengeHandler) error {
+	if h == nil {
+		return errors.New("login: handler is nil")
+	}
+	if s.c.cfg.ClientID == "" || s.c.cfg.XClientID == "" {
+		return errors.New("login: client credentials required")
+	}
+	step := loginStart
+	captchaTries := 0
+	for step != loginDone {
+		switch step {
+		case loginStart:
+			types, err := s.CreateAuthSession(ctx, login)
+			if err != nil {
+				return fmt.Errorf("login: start: %w", err)
+			}
+			method, err := chooseAuthMethod(ctx, h, types.Result.AuthTypes)
+			if err != nil {
+				return err
+			}
+			switch method {
+			case AuthMethodPassword:
+				step = loginPassword
+			case AuthMethodMagicLink:
+				step = loginLetter
+			case AuthMethodQR:
+				step = loginQR
+			default:
+				return fmt.Errorf("login: unsupported method %q", method)
+			}
+		case loginPassword:
+			pw, err := h.Password(ctx, login)
+			if err != nil {
+				return err
+			}
+			base, err := s.AuthorizeByAppPassword(ctx, pw)
+			if err != nil {
+				return fmt.Errorf("login: password: %w", err)
+			}
+			switch {
+			case strings.EqualFold(base.Status, "ok"):
+				step = loginToken
+			case hasAuthError(base.Errors, "captcha.required"):
+				step = loginCaptcha
+			default:
+				return fmt.Errorf("login: password: %s %v", base.Status, base.Errors)
+			}
+		case loginCaptcha:
+			if captchaTries++; captchaTries > loginCaptchaTries {
+				return errors.New("login: captcha not solved")
+			}
+			cp, err := s.GetCaptcha(ctx)
+			if err != nil {
+				return fmt.Errorf("login: captcha: %w", err)
+			}
+			answer, err := h.Captcha(ctx, cp.ImageUrl)
+			if err != nil {
+				return err
+			}
+			base, err := s.AuthorizeByCaptcha(ctx, answer)
+			if err != nil {
+				return fmt.Errorf("login: captcha: %w", err)
+			}
+			if strings.EqualFold(base.Status, "ok") {
+				step = loginPassword
+			}
+		case loginLetter:
+			if _, err := s.GetAuthLetter(ctx); err != nil {
+				return fmt.Errorf("login: letter: %w", err)
+			}
+			if err := h.ConfirmEmail(ctx, login); err != nil {
+				return err
+			}
+			if err := s.waitLetter(ctx); err != nil {
+				return err
+			}
+			step = loginToken
+		case loginQR:
+			link, err := s.GetAuthQRLink(ctx)
+			if err != nil {
+				return fmt.Errorf("login: qr: %w", err)
+			}
+			if err := h.ShowQR(ctx, link); err != nil {
+				return err
+			}
+			var onStatus func(*models.AuthQRStatus)
+			if sh, ok := h.(QRStatusHandler); ok {
+				onStatus = func(st *models.AuthQRStatus) { sh.QRStatus(ctx, st) }
+			}
+			if _, err := s.AuthorizeByQRWithStatus(ctx, loginPollInterval, loginConfirmWait, onStatus); err != nil {
+				return fmt.Errorf("login: qr: %w", err)
+			}
+			step = loginToken
+		case loginToken:
+			acc, err := s.GetAccessToken(ctx)
+			if err != nil {
+				return fmt.Errorf("login: access token: %w", err)
+			}
+			if err := s.Authorize(ctx, acc.AccessToken); err != nil {
+				return fmt.Errorf("login: authorize: %w", err)
+			}
+			step = loginDone
+		}
+	}
+	return nil
+}
+
+// waitLetter опрашивает AuthorizeByLetter до подтверждения ссылки из письма.
+func (s *UserService) waitLetter(ctx context.Context) error {
+	deadline := time.Now().Add(loginConfirmWait)
+	for {
+		st, err := s.AuthorizeByLetter(ctx)
+		if err == nil {
+			return nil
+		}
+		if st == nil {
+			return fmt.Errorf("login: letter: %w", err)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("login: letter timeout: %s", st.Status)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(loginPollInterval):
+		}
+	}
+}
+
+func chooseAuthMethod(ctx context.Context, h ChallengeHandler, methods []string) (string, error) {
+	if mc, ok := h.(MethodChooser); ok {
+		return mc.ChooseMethod(ctx, methods)
+	}
+	for _, m := range methods {
+		switch m {
+		case AuthMethodPassword, AuthMethodMagicLink, AuthMethodQR:
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %v", ErrNoAuthMethod, methods)
+}
+
+func hasAuthError(errs []string, code string) bool {
+	for _, e := range errs {
+		if e == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/models"
+)
+
+type scriptedHandler struct {
+	captchas []string
+	qr       string
+	emails   int
+}
+
+func (h *scriptedHandler) Captcha(_ context.Context, imageURL string) (string, error) {
+	h.captchas = append(h.captchas, imageURL)
+	return "answer", nil
+}
+func (h *scriptedHandler) Password(context.Context, string) (string, error) { return "pw", nil }
+func (h *scriptedHandler) ShowQR(_ context.Context, link string) error {
+	h.qr = link
+	return nil
+}
+func (h *scriptedHandler) ConfirmEmail(context.Context, string) error {
+	h.emails++
+	return nil
+}
+
+// newPassportServer эмулирует passport/mobileproxy/oauth/api; methods — preferred_auth_methods.
+func newPassportServer(t *testing.T, methods []string, calls map[string]int) *httptest.Server {
+	return httptest.NewServer(passportHandler(t, methods, calls))
+}
+
+func passportHandler(t *testing.T, methods []string, calls map[string]int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		_ = r.ParseForm()
+		switch r.URL.Path {
+		case "/am":
+			_, _ = w.Write([]byte(`<input name="csrf_token" value="csrf1"/>`))
+		case "/registration-validations/auth/multi_step/start":
+			if r.PostForm.Get("login") != "user" || r.PostForm.Get("csrf_token") != "csrf1" {
+				t.Fatalf("start form %v", r.PostForm)
+			}
+			_ = json.NewEncoder(w).Encode(models.AuthTypes{TrackId: "tr", AuthTypes: methods})
+		case "/registration-validations/auth/multi_step/commit_password":
+			if calls[r.URL.Path] == 1 {
+				_, _ = w.Write([]byte(`{"status":"error","errors":["captcha.required"],"track_id":"tr"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"ok","track_id":"tr"}`))
+		case "/registration-validations/textcaptcha":
+			_, _ = w.Write([]byte(`{"status":"ok","image_url":"https://captcha/img"}`))
+		case "/registration-validations/checkHuman":
+			if r.PostForm.Get("answer") != "answer" {
+				t.Fatalf("captcha answer %v", r.PostForm)
+			}
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+		case "/registration-validations/auth/password/submit":
+			_, _ = w.Write([]byte(`{"status":"ok","track_id":"qr-track","csrf_token":"csrf2"}`))
+		case "/auth/new/magic/status/", "/auth/letter/status/":
+			_, _ = w.Write([]byte(`{"status":"ok","magic_link_confirmed":true}`))
+		case "/registration-validations/auth/send_magic_letter":
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+		case "/1/bundle/oauth/token_by_sessionid":
+			_, _ = w.Write([]byte(`{"access_token":"x-token","expires_in":31536000}`))
+		case "/1/token":
+			if r.PostForm.Get("access_token") != "x-token" {
+				t.Fatalf("exchange form %v", r.PostForm)
+			}
+			_, _ = w.Write([]byte(`{"access_token":"music","expires_in":3600}`))
+		case "/account/status":
+			if r.Header.Get("Authorization") != "OAuth music" {
+				t.Fatalf("authorization %s", r.Header.Get("Authorization"))
+			}
+			res := models.Response[sdk.UserAuthResult]{}
+			res.Result.Account.Uid = "100"
+			res.Result.Account.Login = "user"
+			_ = json.NewEncoder(w).Encode(res)
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}
+}
+
+func newLoginClient(srv *httptest.Server, st *auth.Storage) *sdk.Client {
+	u, _ := url.Parse(srv.URL)
+	return sdk.New(
+		sdk.WithBaseURL(srv.URL),
+		sdk.WithHTTPClient(&http.Client{Transport: rewriteTransport{target: u}}),
+		sdk.WithAuthStorage(st),
+		sdk.WithClientCredentials("cid", "cs"),
+		sdk.WithXClientCredentials("xid", "xs"),
+	)
+}
+
+func TestLoginPasswordWithCaptcha(t *testing.T) {
+	calls := map[string]int{}
+	srv := newPassportServer(t, []string{"password", "magic_link"}, calls)
+	defer srv.Close()
+	st := auth.New("")
+	h := &scriptedHandler{}
+	if err := newLoginClient(srv, st).User.Login(context.Background(), "user", h); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if len(h.captchas) != 1 || h.captchas[0] != "https://captcha/img" {
+		t.Fatalf("captcha not requested: %v", h.captchas)
+	}
+	if calls["/registration-validations/auth/multi_step/commit_password"] != 2 {
+		t.Fatalf("expected password retry after captcha: %v", calls)
+	}
+	if st.GetToken() != "music" || st.UID() != "100" || !st.Authorized() {
+		t.Fatalf("storage not authorized %+v", st)
+	}
+}
+
+func TestLoginQR(t *testing.T) {
+	calls := map[string]int{}
+	srv := newPassportServer(t, []string{"magic_x_token"}, calls)
+	defer srv.Close()
+	st := auth.New("")
+	h := &scriptedHandler{}
+	if err := newLoginClient(srv, st).User.Login(context.Background(), "user", h); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if h.qr != "https://passport.yandex.ru/auth/magic/code/?track_id=qr-track" {
+		t.Fatalf("qr link %q", h.qr)
+	}
+	if tok := st.GetToken(); tok != "music" {
+		t.Fatalf("token %q", tok)
+	}
+}
+
+func TestLoginLetter(t *testing.T) {
+	calls := map[string]int{}
+	srv := newPassportServer(t, []string{"sms_code", "magic_link"}, calls)
+	defer srv.Close()
+	h := &scriptedHandler{}
+	if err := newLoginClient(srv, auth.New("")).User.Login(context.Background(), "user", h); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if h.emails != 1 || calls["/registration-validations/auth/send_magic_letter"] != 1 {
+		t.Fatalf("letter flow not used: %v", calls)
+	}
+}
+
+func TestLoginNoSupportedMethod(t *testing.T) {
+	srv := newPassportServer(t, []string{"sms_code"}, map[string]int{})
+	defer srv.Close()
+	err := newLoginClient(srv, auth.New("")).User.Login(context.Background(), "user", &scriptedHandler{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+)
+
+// multipartFile тело запроса multipart/form-data с одним файлом (newRequest
+// отправляет его как multipart).
+type multipartFile struct {
+	Field    string
+	FileName string
+	// ContentType части; пусто — application/octet-stream.
+	ContentType string
+	Data        []byte
+}
+
+// encodeMultipart кодирует файл и возвращает тело с заголовком Content-Type.
+func encodeMultipart(f multipartFile) (io.Reader, string, error) {
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	var part io.Writer
+	var err error
+	if f.ContentType == "" {
+		part, err = mw.CreateFormFile(f.Field, f.FileName)
+	} else {
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", `form-data; name="`+f.Field+`"; filename="`+f.FileName+`"`)
+		h.Set("Content-Type", f.ContentType)
+		part, err = mw.CreatePart(h)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(f.Data); err != nil {
+		return nil, "", err
+	}
+	if err := mw.Close(); err != nil {
+		return nil, "", err
+	}
+	return bytes.NewReader(body.Bytes()), mw.FormDataContentType(), nil
+}
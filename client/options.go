@@ -1,9 +1,13 @@
 package client
 
 import (
+	"context"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/Banjirome/yandex-music-go/auth"
+	"github.com/Banjirome/yandex-music-go/cache"
 )
 
 type Option func(*Config)
@@ -18,12 +22,52 @@ type Config struct {
 	XClientID          string
 	XClientSecret      string
 	MobileProxyBaseURL string
+	// YnisonRedirectURL адрес websocket редиректора Ynison.
+	YnisonRedirectURL string
+	// YnisonStateURL шаблон адреса state websocket; {host} заменяется на host из ответа редиректора.
+	YnisonStateURL string
+	// Store постоянное хранилище авторизации: загружается в New и обновляется при смене учётных данных.
+	Store auth.Store
+	// RefreshSkew за сколько до истечения Token обновлять его заранее.
+	RefreshSkew time.Duration
+	// OnTokenRefreshed вызывается после получения нового music token (GetAccessToken, авто-обновление).
+	OnTokenRefreshed func(tok *auth.AccessToken)
+	// RateLimiter ограничивает частоту API запросов (например, *rate.Limiter из golang.org/x/time/rate).
+	RateLimiter RateLimiter
+	// Proxies прокси по направлениям; ключ "" — для всех направлений без собственного.
+	// Если не задано, используется auth.Storage.Proxy на момент New.
+	Proxies map[Route]*url.URL
+	// Transports собственный RoundTripper для направления (приоритетнее Proxies).
+	Transports map[Route]http.RoundTripper
+	// Cache хранилище ответов API (nil — без кеша); CacheRules задают кешируемые пути и TTL.
+	Cache      cache.Store
+	CacheRules []CacheRule
+	// Coalesce объединяет одинаковые одновременные GET (и читающие POST) в один запрос;
+	// CoalescePrefixes ограничивает его путями API (пусто — все пути).
+	Coalesce         bool
+	CoalescePrefixes []string
+	// BatchSize максимум id в одном запросе пакетных методов (Track.Get, Album.GetMany, ...);
+	// BatchParallelism — сколько чанков запрашивается одновременно.
+	BatchSize        int
+	BatchParallelism int
+	// PlaylistEditAttempts сколько раз Playlist.Edit пробует применить изменения
+	// при конфликтах ревизии (по умолчанию 3).
+	PlaylistEditAttempts int
+}
+
+// RateLimiter блокирует до разрешения на следующий запрос.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
 }
 
 func defaultConfig() Config {
 	return Config{
-		BaseURL:   "https://api.music.yandex.net/",
-		UserAgent: "yandex-music-go/0.1.0",
+		BaseURL:     "https://api.music.yandex.net/",
+		UserAgent:   "yandex-music-go/0.1.0",
+		RefreshSkew: time.Minute,
+
+		YnisonRedirectURL: "wss://ynison.music.yandex.ru/redirector.YnisonRedirectService/GetRedirectToYnison",
+		YnisonStateURL:    "wss://{host}/ynison_state.YnisonStateService/PutYnisonState",
 	}
 }
 
@@ -35,11 +79,77 @@ func WithToken(token string) Option {
 		if c.AuthStorage == nil {
 			c.AuthStorage = auth.New(token)
 		} else {
-			c.AuthStorage.Token = token
+			c.AuthStorage.SetToken(token, time.Time{})
 		}
 	}
 }
 func WithAuthStorage(s *auth.Storage) Option { return func(c *Config) { c.AuthStorage = s } }
+
+// WithRefreshSkew задаёт запас времени для упреждающего обновления токена.
+func WithRefreshSkew(d time.Duration) Option { return func(c *Config) { c.RefreshSkew = d } }
+
+// WithOnTokenRefreshed регистрирует hook, вызываемый после обновления токена (например, для сохранения).
+func WithOnTokenRefreshed(fn func(tok *auth.AccessToken)) Option {
+	return func(c *Config) { c.OnTokenRefreshed = fn }
+}
+
+// WithProxy направляет все запросы через прокси (http://, https:// — CONNECT; socks5://, socks5h://).
+func WithProxy(u *url.URL) Option { return WithRouteProxy("", u) }
+
+// WithRouteProxy задаёт прокси для одного направления (RouteAPI, RouteStorage, RoutePassport, RouteYnison).
+func WithRouteProxy(r Route, u *url.URL) Option {
+	return func(c *Config) {
+		if c.Proxies == nil {
+			c.Proxies = map[Route]*url.URL{}
+		}
+		c.Proxies[r] = u
+	}
+}
+
+// WithRouteTransport задаёт собственный RoundTripper для направления.
+func WithRouteTransport(r Route, rt http.RoundTripper) Option {
+	return func(c *Config) {
+		if c.Transports == nil {
+			c.Transports = map[Route]http.RoundTripper{}
+		}
+		c.Transports[r] = rt
+	}
+}
+
+// WithCache включает кеш ответов (cache.NewLRU, cache.NewDisk); без rules используются DefaultCacheRules.
+// Хранилище можно разделять между клиентами (например, через общие опции AccountPool):
+// ответы по правилам с PerUser хранятся отдельно для каждого токена.
+func WithCache(st cache.Store, rules ...CacheRule) Option {
+	return func(c *Config) {
+		c.Cache = st
+		c.CacheRules = rules
+		if len(rules) == 0 {
+			c.CacheRules = DefaultCacheRules
+		}
+	}
+}
+
+// WithCoalescing включает объединение одинаковых одновременных запросов (тот же путь, query,
+// тело и токен). prefixes ограничивают его сервисами по пути API, например "albums", "tracks";
+// без аргументов объединяются все читающие запросы. Изменения никогда не объединяются.
+func WithCoalescing(prefixes ...string) Option {
+	return func(c *Config) { c.Coalesce, c.CoalescePrefixes = true, prefixes }
+}
+
+// WithBatching задаёт размер чанка и число параллельных запросов для пакетных методов
+// (по умолчанию 200 и 4; значения <= 0 оставляют умолчания).
+func WithBatching(size, parallelism int) Option {
+	return func(c *Config) { c.BatchSize, c.BatchParallelism = size, parallelism }
+}
+
+// WithPlaylistEditAttempts задаёт число попыток Playlist.Edit при конфликтах ревизии.
+func WithPlaylistEditAttempts(n int) Option { return func(c *Config) { c.PlaylistEditAttempts = n } }
+
+// WithRateLimiter задаёт ограничитель частоты запросов; может быть общим для нескольких клиентов.
+func WithRateLimiter(rl RateLimiter) Option { return func(c *Config) { c.RateLimiter = rl } }
+
+// WithStore подключает постоянное хранилище токенов (auth.FileStore, auth.EnvStore).
+func WithStore(st auth.Store) Option { return func(c *Config) { c.Store = st } }
 func WithClientCredentials(id, secret string) Option {
 	return func(c *Config) { c.ClientID, c.ClientSecret = id, secret }
 }
@@ -47,3 +157,9 @@ func WithXClientCredentials(id, secret string) Option {
 	return func(c *Config) { c.XClientID, c.XClientSecret = id, secret }
 }
 func WithMobileProxyBaseURL(u string) Option { return func(c *Config) { c.MobileProxyBaseURL = u } }
+
+// WithYnisonURLs переопределяет адреса Ynison (например, для тестового сервера ymtest/ynison).
+// stateURL — шаблон с {host} под host из ответа редиректора (прежний %s тоже понимается).
+func WithYnisonURLs(redirectURL, stateURL string) Option {
+	return func(c *Config) { c.YnisonRedirectURL, c.YnisonStateURL = redirectURL, stateURL }
+}
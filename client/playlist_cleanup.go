@@ -0,0 +1,268 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/playlist"
+	"github.com/Banjirome/yandex-music-go/playlistfmt"
+)
+
+// CleanupReason причина удаления трека при Cleanup.
+type CleanupReason string
+
+const (
+	// CleanupDuplicate повтор того же id трека.
+	CleanupDuplicate CleanupReason = "duplicate"
+	// CleanupSameRecording та же запись под другим id/альбомом (переиздание, ремастер) или
+	// повтор id, если ExactDuplicates выключен.
+	CleanupSameRecording CleanupReason = "same-recording"
+	// CleanupUnavailable трек изъят из каталога (Available == false).
+	CleanupUnavailable CleanupReason = "unavailable"
+)
+
+// CleanupRules какие проверки выполняет Cleanup. Если ни одна проверка не включена,
+// выполняются все.
+type CleanupRules struct {
+	// ExactDuplicates удалять повторы одного id (остаётся первое вхождение).
+	ExactDuplicates bool
+	// SameRecording удалять одну и ту же запись из разных альбомов: совпадают
+	// нормализованные название и версия (кроме ремастеров), хотя бы один исполнитель,
+	// а длительности известны и отличаются не больше DurationTolerance.
+	SameRecording bool
+	// Unavailable удалять изъятые треки.
+	Unavailable bool
+	// DurationTolerance допуск по длительности (по умолчанию 3с).
+	DurationTolerance time.Duration
+	// DryRun только построить отчёт и diff, не меняя плейлист.
+	DryRun bool
+}
+
+// CleanupRemoval удаляемое вхождение.
+type CleanupRemoval struct {
+	// Index позиция в плейлисте, по которому строился план.
+	Index  int
+	Track  playlist.TrackKey
+	Title  string
+	Reason CleanupReason
+	// Kept позиция оставленного дубликата (-1 для CleanupUnavailable).
+	Kept int
+}
+
+// CleanupReport результат Cleanup.
+type CleanupReport struct {
+	Removals []CleanupRemoval
+	// Changes операции change-relative, которые отправлены (или были бы отправлены при DryRun).
+	Changes []playlist.ChangeRequest
+	// Playlist плейлист после изменения (при DryRun — исходный).
+	Playlist playlist.Playlist
+}
+
+// Cleanup находит в плейлисте дубликаты и изъятые треки по правилам rules и удаляет
+// их одним запросом change-relative. Метаданные треков, которых нет в pl.Tracks,
+// запрашиваются пакетно. При ErrRevisionConflict плейлист перечитывается и план
+// строится заново (не более Config.PlaylistEditAttempts раз); pl обновляется до
+// последнего прочитанного состояния.
+func (s *PlaylistService) Cleanup(ctx context.Context, pl *playlist.Playlist, rules CleanupRules) (*CleanupReport, error) {
+	if pl == nil {
+		return nil, fmt.Errorf("playlist nil")
+	}
+	if !rules.ExactDuplicates && !rules.SameRecording && !rules.Unavailable {
+		rules.ExactDuplicates, rules.SameRecording, rules.Unavailable = true, true, true
+	}
+	if rules.DurationTolerance <= 0 {
+		rules.DurationTolerance = playlistfmt.DefaultDurationTolerance
+	}
+	attempts := s.c.cfg.PlaylistEditAttempts
+	if attempts <= 0 {
+		attempts = defaultEditAttempts
+	}
+	for attempt := 1; ; attempt++ {
+		tracks, err := s.cleanupTracks(ctx, pl)
+		if err != nil {
+			return nil, err
+		}
+		rep := &CleanupReport{Removals: planCleanup(tracks, rules), Playlist: *pl}
+		drop := make(map[int]bool, len(rep.Removals))
+		for _, r := range rep.Removals {
+			drop[r.Index] = true
+		}
+		// Index — позиция в pl.Tracks, а Keys пропускает контейнеры без id
+		cur := pl.Keys()
+		desired := make([]playlist.TrackKey, 0, len(cur))
+		for i, tc := range pl.Tracks {
+			if k := tc.Key(); k.Id != "" && !drop[i] {
+				desired = append(desired, k)
+			}
+		}
+		rep.Changes = playlist.Diff(cur, desired)
+		if rules.DryRun || len(rep.Changes) == 0 {
+			return rep, nil
+		}
+		res, err := s.applyAndGet(ctx, pl, rep.Changes)
+		if err == nil {
+			rep.Playlist = res.Result
+			return rep, nil
+		}
+		if !errors.Is(err, ErrRevisionConflict) {
+			return nil, err
+		}
+		if attempt >= attempts {
+			return nil, fmt.Errorf("%w: gave up after %d attempts", err, attempts)
+		}
+		s.c.InvalidateCache(path.Join("users", ownerUID(pl), "playlists", pl.Kind))
+		fresh, err := s.Get(withoutCoalescing(ctx), ownerUID(pl), pl.Kind)
+		if err != nil {
+			return nil, err
+		}
+		*pl = fresh.Result
+	}
+}
+
+// cleanupTrack трек плейлиста с метаданными для Cleanup.
+type cleanupTrack struct {
+	key        playlist.TrackKey
+	title      string
+	version    string
+	artists    []string
+	durationMs int64
+	available  bool
+}
+
+// cleanupTracks метаданные треков pl по позициям; недостающие дозапрашиваются через Track.GetReport.
+func (s *PlaylistService) cleanupTracks(ctx context.Context, pl *playlist.Playlist) ([]cleanupTrack, error) {
+	out := make([]cleanupTrack, len(pl.Tracks))
+	var missing []string
+	for i, tc := range pl.Tracks {
+		out[i] = cleanupTrack{key: tc.Key(), available: true}
+		t := tc.Track
+		if t == nil {
+			if out[i].key.Id != "" {
+				missing = append(missing, out[i].key.String())
+			}
+			continue
+		}
+		out[i].title, out[i].version, out[i].durationMs = t.Title, t.Version, t.DurationMs
+		out[i].available = t.Available == nil || *t.Available
+		for _, a := range t.Artists {
+			out[i].artists = append(out[i].artists, a.Name)
+		}
+	}
+	if len(missing) == 0 {
+		return out, nil
+	}
+	meta, err := s.c.Track.GetReport(ctx, missing...)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]Track, len(meta.Items))
+	for _, t := range meta.Items {
+		byID[t.ID] = t
+	}
+	for i := range out {
+		t, ok := byID[out[i].key.Id]
+		if !ok || pl.Tracks[i].Track != nil {
+			continue
+		}
+		out[i].title, out[i].version, out[i].durationMs, out[i].available = t.Title, t.Version, t.DurationMs, t.IsAvailable()
+		for _, a := range t.Artists {
+			out[i].artists = append(out[i].artists, a.Name)
+		}
+	}
+	return out, nil
+}
+
+// planCleanup решает, какие позиции удалить. Из двух вхождений одной записи
+// остаётся более раннее, если только оно не изъято, а более позднее доступно.
+func planCleanup(tracks []cleanupTrack, rules CleanupRules) []CleanupRemoval {
+	var out []CleanupRemoval
+	remove := func(i int, reason CleanupReason, kept int) {
+		out = append(out, CleanupRemoval{Index: i, Track: tracks[i].key, Title: tracks[i].title, Reason: reason, Kept: kept})
+	}
+	firstByID := map[string]int{} // id -> позиция, которая остаётся вместо его повторов
+	byTitle := map[string][]int{} // оставленные позиции по ключу названия
+	for i, t := range tracks {
+		if t.key.Id == "" {
+			continue
+		}
+		j, seen := firstByID[t.key.Id]
+		if !seen {
+			firstByID[t.key.Id] = i
+		}
+		if rules.Unavailable && !t.available {
+			remove(i, CleanupUnavailable, -1)
+			continue
+		}
+		if seen && rules.ExactDuplicates {
+			remove(i, CleanupDuplicate, j)
+			continue
+		}
+		// без ExactDuplicates повтор id проверяется как та же запись
+		if !rules.SameRecording || t.title == "" {
+			continue
+		}
+		tk := recordingTitle(t)
+		kept := byTitle[tk]
+		same := -1
+		for n, j := range kept {
+			if sameRecording(tracks[j], t, rules.DurationTolerance) {
+				same = n
+				break
+			}
+		}
+		switch {
+		case same < 0:
+			byTitle[tk] = append(kept, i)
+		case !tracks[kept[same]].available && t.available:
+			old := kept[same]
+			// прежние удаления ссылались на вытесненное вхождение
+			for n := range out {
+				if out[n].Kept == old {
+					out[n].Kept = i
+				}
+			}
+			remove(old, CleanupSameRecording, i)
+			kept[same] = i
+			firstByID[tracks[old].key.Id] = i
+		default:
+			remove(i, CleanupSameRecording, kept[same])
+		}
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].Index < out[b].Index })
+	return out
+}
+
+// recordingTitle нормализованное название с версией; ремастер считается той же записью.
+func recordingTitle(t cleanupTrack) string {
+	title := playlistfmt.Normalize(t.title)
+	if v := playlistfmt.Normalize(t.version); v != "" && !strings.Contains(v, "remaster") {
+		title += " " + v
+	}
+	return title
+}
+
+func sameRecording(a, b cleanupTrack, tolerance time.Duration) bool {
+	if a.durationMs <= 0 || b.durationMs <= 0 {
+		return false
+	}
+	diff := time.Duration(a.durationMs-b.durationMs) * time.Millisecond
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > tolerance {
+		return false
+	}
+	for _, x := range a.artists {
+		for _, y := range b.artists {
+			if nx := playlistfmt.Normalize(x); nx != "" && nx == playlistfmt.Normalize(y) {
+				return true
+			}
+		}
+	}
+	return false
+}
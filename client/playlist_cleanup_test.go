@@ -0,0 +1,166 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
+
+func song(id, title, version string, durationMs int64, available bool) playlist.TrackContainer {
+	return playlist.TrackContainer{ID: id, Track: &playlist.Track{ID: id, Title: title, Version: version, DurationMs: durationMs,
+		Artists: []playlist.TrackArtist{{Name: "Muse"}}, Available: &available}}
+}
+
+func TestPlaylistCleanup(t *testing.T) {
+	ps := &playlistServer{t: t, tracks: []string{"1", "2", "1", "3", "4", "5"}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/tracks" {
+			_ = r.ParseForm()
+			if got := r.PostForm.Get("track-ids"); got != "5" {
+				t.Errorf("track-ids %q", got)
+			}
+			_, _ = w.Write([]byte(`{"result":[{"id":"5","title":"Other","available":false}]}`))
+			return
+		}
+		ps.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL))
+	pl := playlist.Playlist{Kind: "5", Owner: &playlist.Owner{Uid: "u1"}, Tracks: []playlist.TrackContainer{
+		song("1", "Song", "", 200000, true),
+		song("2", "Song", "Remastered 2011", 201000, true),
+		song("1", "Song", "", 200000, true),
+		song("3", "Gone", "", 100000, false),
+		song("4", "Song", "Live", 200000, true),
+		{ID: "5"},
+	}}
+
+	rep, err := c.Playlist.Cleanup(context.Background(), &pl, sdk.CleanupRules{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		index  int
+		reason sdk.CleanupReason
+		kept   int
+	}{{1, sdk.CleanupSameRecording, 0}, {2, sdk.CleanupDuplicate, 0}, {3, sdk.CleanupUnavailable, -1}, {5, sdk.CleanupUnavailable, -1}}
+	if len(rep.Removals) != len(want) {
+		t.Fatalf("removals %+v", rep.Removals)
+	}
+	for i, w := range want {
+		if r := rep.Removals[i]; r.Index != w.index || r.Reason != w.reason || r.Kept != w.kept {
+			t.Fatalf("removal %d: %+v", i, r)
+		}
+	}
+	if ps.posts != 0 || len(rep.Changes) == 0 {
+		t.Fatalf("dry run posted=%d changes=%v", ps.posts, rep.Changes)
+	}
+
+	rep, err = c.Playlist.Cleanup(context.Background(), &pl, sdk.CleanupRules{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps.posts != 1 || trackIDs(rep.Playlist) != "1 4" || pl.Revision != 1 {
+		t.Fatalf("posts=%d tracks=%s rev=%d", ps.posts, trackIDs(rep.Playlist), pl.Revision)
+	}
+}
+
+func TestPlaylistCleanupFetchedVersion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tracks" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":[{"id":"2","title":"Song","version":"Live","durationMs":200000,"artists":[{"name":"Muse"}]}]}`))
+	}))
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL))
+	pl := playlist.Playlist{Kind: "5", Owner: &playlist.Owner{Uid: "u1"}, Tracks: []playlist.TrackContainer{
+		song("1", "Song", "", 200000, true),
+		{ID: "2"}, // концертная версия без Track: версия приходит из /tracks
+	}}
+	rep, err := c.Playlist.Cleanup(context.Background(), &pl, sdk.CleanupRules{SameRecording: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Removals) != 0 {
+		t.Fatalf("live version removed: %+v", rep.Removals)
+	}
+}
+
+func TestPlaylistCleanupKeepsAvailableRecording(t *testing.T) {
+	c := sdk.New(sdk.WithBaseURL("http://127.0.0.1:0"))
+	pl := playlist.Playlist{Kind: "5", Owner: &playlist.Owner{Uid: "u1"}, Tracks: []playlist.TrackContainer{
+		song("1", "Song", "", 200000, false),
+		song("2", "Song", "", 200000, true),
+		song("3", "Song", "", 260000, true),
+	}}
+	rep, err := c.Playlist.Cleanup(context.Background(), &pl, sdk.CleanupRules{SameRecording: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Removals) != 1 || rep.Removals[0].Index != 0 || rep.Removals[0].Kept != 1 {
+		t.Fatalf("removals %+v", rep.Removals)
+	}
+
+	// повтор вытесненного вхождения должен ссылаться на оставленное
+	pl.Tracks = []playlist.TrackContainer{
+		song("1", "Song", "", 200000, false),
+		song("1", "Song", "", 200000, false),
+		song("2", "Song", "", 200000, true),
+		song("1", "Song", "", 200000, false),
+	}
+	rep, err = c.Playlist.Cleanup(context.Background(), &pl, sdk.CleanupRules{SameRecording: true, ExactDuplicates: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Removals) != 3 {
+		t.Fatalf("removals %+v", rep.Removals)
+	}
+	for _, r := range rep.Removals {
+		if r.Kept != 2 {
+			t.Fatalf("removal %+v must point at kept index 2", r)
+		}
+	}
+
+	// повтор того же id — тоже та же запись, даже без ExactDuplicates
+	pl.Tracks = []playlist.TrackContainer{
+		song("1", "Song", "", 200000, true),
+		song("2", "Other", "", 200000, true),
+		song("1", "Song", "", 200000, true),
+	}
+	rep, err = c.Playlist.Cleanup(context.Background(), &pl, sdk.CleanupRules{SameRecording: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Removals) != 1 || rep.Removals[0].Index != 2 || rep.Removals[0].Reason != sdk.CleanupSameRecording || rep.Removals[0].Kept != 0 {
+		t.Fatalf("removals %+v", rep.Removals)
+	}
+}
+
+func TestPlaylistCleanupSkipsIDlessContainers(t *testing.T) {
+	ps := &playlistServer{t: t, tracks: []string{"1", "1", "2"}}
+	srv := httptest.NewServer(ps)
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL))
+	pl := playlist.Playlist{Kind: "5", Owner: &playlist.Owner{Uid: "u1"}, Tracks: []playlist.TrackContainer{
+		{}, // контейнер без id (например, удалённый локальный файл) не попадает в Keys
+		song("1", "A", "", 100000, true),
+		song("1", "A", "", 100000, true),
+		song("2", "B", "", 200000, true),
+	}}
+	rep, err := c.Playlist.Cleanup(context.Background(), &pl, sdk.CleanupRules{ExactDuplicates: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Removals) != 1 || rep.Removals[0].Index != 2 || rep.Removals[0].Kept != 1 {
+		t.Fatalf("removals %+v", rep.Removals)
+	}
+	if got := trackIDs(rep.Playlist); got != "1 2" {
+		t.Fatalf("tracks after cleanup %q", got)
+	}
+}
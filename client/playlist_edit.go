@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/Banjirome/yandex-music-go/models"
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
+
+// ErrRevisionConflict плейлист изменён с момента чтения (ревизия устарела).
+var ErrRevisionConflict = errors.New("playlist revision conflict")
+
+// defaultEditAttempts попыток Edit по умолчанию (см. WithPlaylistEditAttempts).
+const defaultEditAttempts = 3
+
+// Edit применяет намерение e к плейлисту с оптимистичной блокировкой: diff строится
+// от pl и его ревизии, а при ErrRevisionConflict плейлист перечитывается, намерение
+// применяется к свежему содержимому и запрос повторяется (не более
+// Config.PlaylistEditAttempts раз). pl обновляется до последнего прочитанного состояния.
+func (s *PlaylistService) Edit(ctx context.Context, pl *playlist.Playlist, e playlist.Edit) (*models.Response[playlist.Playlist], error) {
+	if pl == nil {
+		return nil, fmt.Errorf("playlist nil")
+	}
+	attempts := s.c.cfg.PlaylistEditAttempts
+	if attempts <= 0 {
+		attempts = defaultEditAttempts
+	}
+	for attempt := 1; ; attempt++ {
+		cur := pl.Keys()
+		changes := playlist.Diff(cur, e.Apply(cur))
+		if len(changes) == 0 {
+			return &models.Response[playlist.Playlist]{Result: *pl}, nil
+		}
+		res, err := s.applyAndGet(ctx, pl, changes)
+		if err == nil {
+			return res, nil
+		}
+		if !errors.Is(err, ErrRevisionConflict) {
+			return nil, err
+		}
+		if attempt >= attempts {
+			return nil, fmt.Errorf("%w: gave up after %d attempts", err, attempts)
+		}
+		// кешированная копия заведомо устарела
+		s.c.InvalidateCache(path.Join("users", ownerUID(pl), "playlists", pl.Kind))
+		fresh, err := s.Get(withoutCoalescing(ctx), ownerUID(pl), pl.Kind)
+		if err != nil {
+			return nil, err
+		}
+		*pl = fresh.Result
+	}
+}
@@ -0,0 +1,78 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/models"
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
+
+// сервер с общим плейлистом: другой «бот» успевает изменить его перед нашим первым запросом
+func TestPlaylistEditRebasesOnConflict(t *testing.T) {
+	rev := 2
+	tracks := []string{"a", "b", "z"} // z добавлен конкурентом
+	var diffs []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/users/u1/playlists/5":
+			_ = json.NewEncoder(w).Encode(models.Response[playlist.Playlist]{Result: playlist.Playlist{Kind: "5", Revision: rev, Owner: &playlist.Owner{Uid: "u1"}, Tracks: containers(tracks...)}})
+		case r.Method == http.MethodPost && r.URL.Path == "/users/u1/playlists/5/change-relative":
+			_ = r.ParseForm()
+			diffs = append(diffs, r.PostForm.Get("diff"))
+			if r.PostForm.Get("revision") != "2" {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			tracks = []string{"c", "b", "z"}
+			rev = 3
+			_ = json.NewEncoder(w).Encode(models.Response[playlist.Playlist]{Result: playlist.Playlist{Kind: "5", Revision: rev}})
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL))
+	stale := playlist.Playlist{Kind: "5", Revision: 1, Owner: &playlist.Owner{Uid: "u1"}, Tracks: containers("a", "b")}
+	e := playlist.Edit{Remove: []playlist.TrackKey{{Id: "a"}}, Insert: []playlist.Insert{{At: 0, Tracks: []playlist.TrackKey{{Id: "c"}}}}}
+
+	res, err := c.Playlist.Edit(context.Background(), &stale, e)
+	if err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	if len(diffs) != 2 || stale.Revision != 3 || res.Result.Revision != 3 {
+		t.Fatalf("expected retry after conflict: diffs=%v rev=%d", diffs, stale.Revision)
+	}
+	// вторая попытка построена от свежего содержимого: удаляет a (0..1), z не трогает
+	var ops []playlist.ChangeRequest
+	_ = json.Unmarshal([]byte(diffs[1]), &ops)
+	want := playlist.Diff([]playlist.TrackKey{{Id: "a"}, {Id: "b"}, {Id: "z"}}, []playlist.TrackKey{{Id: "c"}, {Id: "b"}, {Id: "z"}})
+	if len(ops) != len(want) || ops[0].Operation != want[0].Operation || ops[0].From != want[0].From {
+		t.Fatalf("rebased diff %+v, want %+v", ops, want)
+	}
+}
+
+func TestPlaylistEditGivesUp(t *testing.T) {
+	posts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posts++
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"wrong-revision","message":"revision conflict"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(models.Response[playlist.Playlist]{Result: playlist.Playlist{Kind: "5", Revision: 9, Tracks: containers("a")}})
+	}))
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithPlaylistEditAttempts(2))
+	pl := playlist.Playlist{Kind: "5", Owner: &playlist.Owner{Uid: "u1"}, Tracks: containers("a")}
+	_, err := c.Playlist.Edit(context.Background(), &pl, playlist.Edit{Insert: []playlist.Insert{{At: -1, Tracks: []playlist.TrackKey{{Id: "b"}}}}})
+	if !errors.Is(err, sdk.ErrRevisionConflict) || posts != 2 {
+		t.Fatalf("expected ErrRevisionConflict after 2 posts, got %v (%d posts)", err, posts)
+	}
+}
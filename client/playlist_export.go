@@ -0,0 +1,37 @@
+package client
+
+import "github.com/Banjirome/yandex-music-go/playlistfmt"
+
+// TrackEntry запись playlistfmt для трека.
+func TrackEntry(t Track) playlistfmt.Entry {
+	e := playlistfmt.Entry{ID: t.ID, Title: t.Title, DurationMs: t.DurationMs}
+	for _, ar := range t.Artists {
+		e.Artists = append(e.Artists, ar.Name)
+	}
+	if len(t.Albums) > 0 {
+		e.AlbumID, e.Album = t.Albums[0].ID, t.Albums[0].Title
+	}
+	return e
+}
+
+// TracksList список playlistfmt из треков (например, Track.Get).
+func TracksList(title string, tracks []Track) *playlistfmt.List {
+	l := &playlistfmt.List{Title: title}
+	for _, t := range tracks {
+		l.Entries = append(l.Entries, TrackEntry(t))
+	}
+	return l
+}
+
+// List доступные лайкнутые треки как список playlistfmt.
+func (r *LikedTracksResult) List(title string) *playlistfmt.List {
+	l := &playlistfmt.List{Title: title}
+	for _, lt := range r.Tracks {
+		e := TrackEntry(lt.Track)
+		if lt.AlbumID != "" {
+			e.AlbumID = lt.AlbumID
+		}
+		l.Entries = append(l.Entries, e)
+	}
+	return l
+}
@@ -0,0 +1,235 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/playlist"
+	"github.com/Banjirome/yandex-music-go/playlistfmt"
+	"github.com/Banjirome/yandex-music-go/search"
+)
+
+const (
+	defaultMinConfidence = 0.7
+	defaultMatchResults  = 5
+)
+
+// MatchOptions параметры сопоставления записей чужого плейлиста с каталогом.
+type MatchOptions struct {
+	// MinConfidence минимальная оценка лучшего результата поиска (0..1, по умолчанию 0.7).
+	MinConfidence float64
+	// DurationTolerance допустимое расхождение длительностей (по умолчанию 3с).
+	DurationTolerance time.Duration
+	// Results сколько результатов поиска рассматривать на запись (по умолчанию 5).
+	Results int
+	// Title название создаваемого плейлиста (по умолчанию List.Title).
+	Title string
+	// DryRun только сопоставить, не создавая плейлист.
+	DryRun bool
+}
+
+// EntryMatch результат сопоставления одной записи.
+type EntryMatch struct {
+	// Line порядковый номер записи в файле (с 1).
+	Line  int
+	Entry playlistfmt.Entry
+	// Track лучший найденный кандидат (пустой ID — поиск ничего не вернул).
+	Track playlist.TrackKey
+	// Title и Artists кандидата — для отчёта.
+	Title   string
+	Artists []string
+	// Confidence оценка кандидата (1 — ссылка на music.yandex.ru в файле).
+	Confidence float64
+}
+
+// MatchReport отчёт об импорте плейлиста.
+type MatchReport struct {
+	Matched []EntryMatch
+	// Unmatched записи, у которых лучший кандидат ниже MinConfidence.
+	Unmatched []EntryMatch
+	// Playlist созданный плейлист (nil при DryRun или если ничего не найдено).
+	Playlist *playlist.Playlist
+}
+
+// Keys треки сопоставленных записей в порядке файла.
+func (r *MatchReport) Keys() []playlist.TrackKey {
+	keys := make([]playlist.TrackKey, 0, len(r.Matched))
+	for _, m := range r.Matched {
+		keys = append(keys, m.Track)
+	}
+	return keys
+}
+
+// Match сопоставляет записи списка с каталогом через Search.Tracks (не более
+// Config.BatchParallelism запросов одновременно). Записи со ссылкой на music.yandex.ru
+// принимаются без поиска. Запись с ISRC сначала ищется по коду; найденные треки
+// оцениваются так же, как результаты обычного поиска, и если ни один не набрал
+// MinConfidence, запись ищется по исполнителю и названию. Первая ошибка поиска
+// отменяет остальные запросы.
+func (s *PlaylistService) Match(ctx context.Context, l *playlistfmt.List, opts MatchOptions) (*MatchReport, error) {
+	if l == nil {
+		return nil, fmt.Errorf("playlist list nil")
+	}
+	if opts.MinConfidence <= 0 {
+		opts.MinConfidence = defaultMinConfidence
+	}
+	if opts.Results <= 0 {
+		opts.Results = defaultMatchResults
+	}
+	par := s.c.cfg.BatchParallelism
+	if par <= 0 {
+		par = defaultBatchParallelism
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	out := make([]EntryMatch, len(l.Entries))
+	errs := make([]error, len(l.Entries))
+	sem := make(chan struct{}, par)
+	var wg sync.WaitGroup
+	for i, e := range l.Entries {
+		out[i] = EntryMatch{Line: i + 1, Entry: e}
+		if e.ID != "" {
+			out[i].Track = playlist.TrackKey{Id: e.ID, AlbumId: e.AlbumID}
+			out[i].Title, out[i].Artists, out[i].Confidence = e.Title, e.Artists, 1
+			continue
+		}
+		if e.ISRC == "" && strings.TrimSpace(e.Artist()+" "+e.Title) == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+			if errs[i] = s.matchEntry(ctx, &out[i], opts); errs[i] != nil {
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+	var firstErr error
+	for i, err := range errs {
+		// отмена соседних запросов вторична по отношению к исходной ошибке
+		if err != nil && (firstErr == nil || errors.Is(firstErr, context.Canceled)) {
+			firstErr = fmt.Errorf("search line %d: %w", i+1, err)
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	rep := &MatchReport{}
+	for _, m := range out {
+		if m.Track.Id != "" && m.Confidence >= opts.MinConfidence {
+			rep.Matched = append(rep.Matched, m)
+		} else {
+			rep.Unmatched = append(rep.Unmatched, m)
+		}
+	}
+	return rep, nil
+}
+
+// matchEntry ищет трек для одной записи: по ISRC, затем по исполнителю и названию.
+func (s *PlaylistService) matchEntry(ctx context.Context, m *EntryMatch, opts MatchOptions) error {
+	if m.Entry.ISRC != "" {
+		resp, err := s.c.Search.Tracks(ctx, m.Entry.ISRC, 0, opts.Results)
+		if err != nil {
+			return err
+		}
+		// поиск по коду может вернуть постороннюю запись — проверяем её как обычный кандидат
+		if resp.Result.Tracks != nil {
+			pickBest(m, resp.Result.Tracks.Results, opts.DurationTolerance)
+		}
+		if m.Confidence >= opts.MinConfidence {
+			return nil
+		}
+	}
+	query := strings.TrimSpace(m.Entry.Artist() + " " + m.Entry.Title)
+	if query == "" {
+		return nil
+	}
+	resp, err := s.c.Search.Tracks(ctx, query, 0, opts.Results)
+	if err != nil {
+		return err
+	}
+	if resp.Result.Tracks != nil {
+		pickBest(m, resp.Result.Tracks.Results, opts.DurationTolerance)
+	}
+	return nil
+}
+
+func setCandidate(m *EntryMatch, st search.SearchTrack, confidence float64) {
+	m.Confidence, m.Title, m.Artists = confidence, st.Title, nil
+	for _, a := range st.Artists {
+		m.Artists = append(m.Artists, a.Name)
+	}
+	m.Track = playlist.TrackKey{Id: st.ID}
+	if len(st.Albums) > 0 {
+		m.Track.AlbumId = st.Albums[0].ID
+	}
+}
+
+// pickBest записывает в m кандидата с наибольшей оценкой (изъятые треки пропускаются).
+func pickBest(m *EntryMatch, results []search.SearchTrack, tolerance time.Duration) {
+	for _, st := range results {
+		if st.Available != nil && !*st.Available {
+			continue
+		}
+		cand := playlistfmt.Entry{Title: st.Title, DurationMs: st.DurationMs}
+		if st.Version != "" {
+			cand.Title += " (" + st.Version + ")"
+		}
+		for _, a := range st.Artists {
+			cand.Artists = append(cand.Artists, a.Name)
+		}
+		if score := playlistfmt.Score(m.Entry, cand, tolerance); score > m.Confidence {
+			setCandidate(m, st, score)
+		}
+	}
+}
+
+// ImportList сопоставляет записи (см. Match) и создаёт из найденных треков новый
+// плейлист текущего пользователя в порядке файла. Несопоставленные записи
+// перечисляются в MatchReport.Unmatched.
+func (s *PlaylistService) ImportList(ctx context.Context, l *playlistfmt.List, opts MatchOptions) (*MatchReport, error) {
+	rep, err := s.Match(ctx, l, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.DryRun || len(rep.Matched) == 0 {
+		return rep, nil
+	}
+	uid := s.c.UID()
+	if uid == "" {
+		return nil, fmt.Errorf("user uid not set; call Account.Status first")
+	}
+	title := opts.Title
+	if title == "" {
+		title = l.Title
+	}
+	if title == "" {
+		title = "Imported"
+	}
+	created, err := s.Create(ctx, uid, title)
+	if err != nil {
+		return nil, err
+	}
+	pl := created.Result
+	if pl.Owner == nil {
+		pl.Owner = &playlist.Owner{Uid: uid}
+	}
+	res, err := s.Append(ctx, &pl, rep.Keys())
+	if err != nil {
+		return nil, fmt.Errorf("fill playlist %s: %w", pl.Kind, err)
+	}
+	rep.Playlist = &res.Result
+	return rep, nil
+}
@@ -0,0 +1,109 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/playlistfmt"
+)
+
+func TestPlaylistImportList(t *testing.T) {
+	ps := &playlistServer{t: t}
+	created := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			switch r.URL.Query().Get("text") {
+			case "GBAHT0900320", "GBAHT0900321":
+				_, _ = w.Write([]byte(`{"result":{"tracks":{"results":[{"id":"7","title":"Resistance","artists":[{"name":"Muse"}],"albums":[{"id":"70"}]}]}}}`))
+			case "XX0000000000":
+				_, _ = w.Write([]byte(`{"result":{"tracks":{"results":[]}}}`))
+			case "Muse Uprising":
+				_, _ = w.Write([]byte(`{"result":{"tracks":{"results":[
+					{"id":"8","title":"Uprising","durationMs":305000,"artists":[{"name":"Muse"}],"albums":[{"id":"80"}],"available":false},
+					{"id":"9","title":"Uprising","version":"Live","durationMs":330000,"artists":[{"name":"Muse"}],"albums":[{"id":"90"}]},
+					{"id":"1","title":"Uprising","durationMs":304000,"artists":[{"name":"Muse"}],"albums":[{"id":"10"}]}]}}}`))
+			default:
+				_, _ = w.Write([]byte(`{"result":{"tracks":{"results":[{"id":"5","title":"Something Else","artists":[{"name":"Nobody"}]}]}}}`))
+			}
+		case "/users/u1/playlists/create":
+			created = true
+			_, _ = w.Write([]byte(`{"result":{"kind":"5","title":"Road","revision":0}}`))
+		default:
+			ps.ServeHTTP(w, r)
+		}
+	}))
+	defer srv.Close()
+	st := auth.New("tok")
+	st.SetUid("u1")
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithAuthStorage(st))
+	l := &playlistfmt.List{Title: "Road", Entries: []playlistfmt.Entry{
+		{Title: "Uprising", Artists: []string{"Muse"}, DurationMs: 305000},
+		{Title: "Unknown Song", Artists: []string{"Ghost"}},
+		{ID: "2", AlbumID: "20", Title: "Direct"},
+		// найден по ISRC: поиск по исполнителю и названию дал бы "Something Else"
+		{ISRC: "GBAHT0900320", Title: "Resistance", Artists: []string{"Muse"}},
+		// ISRC не найден: поиск по исполнителю и названию
+		{ISRC: "XX0000000000", Title: "Uprising", Artists: []string{"Muse"}, DurationMs: 305000},
+		// по ISRC нашёлся посторонний трек — он не принимается
+		{ISRC: "GBAHT0900321", Title: "Hysteria", Artists: []string{"Muse"}},
+	}}
+
+	rep, err := c.Playlist.ImportList(context.Background(), l, sdk.MatchOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || rep.Playlist != nil || len(rep.Matched) != 4 || len(rep.Unmatched) != 2 {
+		t.Fatalf("dry run %+v", rep)
+	}
+	if m := rep.Matched[0]; m.Line != 1 || m.Track.Id != "1" || m.Track.AlbumId != "10" || m.Confidence < 0.99 {
+		t.Fatalf("match %+v", m)
+	}
+	if m := rep.Matched[2]; m.Line != 4 || m.Track.Id != "7" || m.Track.AlbumId != "70" || m.Confidence != 1 {
+		t.Fatalf("isrc match %+v", m)
+	}
+	if m := rep.Matched[3]; m.Line != 5 || m.Track.Id != "1" {
+		t.Fatalf("isrc fallback %+v", m)
+	}
+	if u := rep.Unmatched[0]; u.Line != 2 || u.Track.Id != "5" || u.Confidence >= 0.7 {
+		t.Fatalf("unmatched %+v", u)
+	}
+	if u := rep.Unmatched[1]; u.Line != 6 || u.Confidence >= 0.7 {
+		t.Fatalf("unrelated isrc hit accepted %+v", u)
+	}
+
+	rep, err = c.Playlist.ImportList(context.Background(), l, sdk.MatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created || rep.Playlist == nil || trackIDs(*rep.Playlist) != "1 2 7 1" {
+		t.Fatalf("import %+v", rep.Playlist)
+	}
+}
+
+func TestPlaylistMatchCancelsOnError(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithBatching(10, 1))
+	l := &playlistfmt.List{}
+	for i := 0; i < 20; i++ {
+		l.Entries = append(l.Entries, playlistfmt.Entry{Title: "Song " + strconv.Itoa(i), Artists: []string{"A"}})
+	}
+	if _, err := c.Playlist.Match(context.Background(), l, sdk.MatchOptions{}); err == nil || errors.Is(err, context.Canceled) {
+		t.Fatalf("expected search error, got %v", err)
+	}
+	if n := atomic.LoadInt32(&hits); n > 2 {
+		t.Fatalf("remaining searches not cancelled: %d requests", n)
+	}
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/Banjirome/yandex-music-go/models"
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
+
+// SetVisibility делает плейлист публичным или приватным.
+func (s *PlaylistService) SetVisibility(ctx context.Context, userUID, kind string, v playlist.Visibility) (*models.Response[playlist.Playlist], error) {
+	if v != playlist.VisibilityPublic && v != playlist.VisibilityPrivate {
+		return nil, fmt.Errorf("unknown playlist visibility %q", v)
+	}
+	form := url.Values{}
+	form.Set("value", string(v))
+	p := path.Join("users", userUID, "playlists", kind, "visibility")
+	return s.postFormPlaylist(ctx, p, form)
+}
+
+// SetDescription задаёт описание плейлиста.
+func (s *PlaylistService) SetDescription(ctx context.Context, userUID, kind, description string) (*models.Response[playlist.Playlist], error) {
+	form := url.Values{}
+	form.Set("value", description)
+	p := path.Join("users", userUID, "playlists", kind, "description")
+	return s.postFormPlaylist(ctx, p, form)
+}
+
+// ClearDescription удаляет описание плейлиста.
+func (s *PlaylistService) ClearDescription(ctx context.Context, userUID, kind string) (*models.Response[playlist.Playlist], error) {
+	return s.SetDescription(ctx, userUID, kind, "")
+}
+
+// UploadCover загружает пользовательскую обложку (JPEG или PNG) multipart запросом
+// POST users/{uid}/playlists/{kind}/cover/upload.
+func (s *PlaylistService) UploadCover(ctx context.Context, userUID, kind string, image []byte) (*models.Response[playlist.Cover], error) {
+	ct := http.DetectContentType(image)
+	name := "cover.jpg"
+	switch ct {
+	case "image/jpeg":
+	case "image/png":
+		name = "cover.png"
+	default:
+		return nil, fmt.Errorf("upload cover: unsupported image type %s", ct)
+	}
+	p := path.Join("users", userUID, "playlists", kind, "cover", "upload")
+	return doJSON[playlist.Cover](s.c, ctx, http.MethodPost, p, nil, multipartFile{Field: "image", FileName: name, ContentType: ct, Data: image})
+}
+
+// ClearCover удаляет пользовательскую обложку (возвращается автоматическая).
+func (s *PlaylistService) ClearCover(ctx context.Context, userUID, kind string) error {
+	p := path.Join("users", userUID, "playlists", kind, "cover", "clear")
+	resp, err := s.postForm(ctx, p, url.Values{})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("clear playlist cover: %s", resp.Status)
+	}
+	return nil
+}
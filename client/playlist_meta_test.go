@@ -0,0 +1,93 @@
+package client_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/playlist"
+	"github.com/Banjirome/yandex-music-go/ugc"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestPlaylistMetadata(t *testing.T) {
+	seen := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/u1/playlists/5/visibility", "/users/u1/playlists/5/description":
+			_ = r.ParseForm()
+			seen[r.URL.Path] = r.PostForm.Get("value")
+			_, _ = w.Write([]byte(`{"result":{"kind":"5","revision":2}}`))
+		case "/users/u1/playlists/5/cover/upload":
+			f, hdr, err := r.FormFile("image")
+			if err != nil {
+				t.Errorf("multipart: %v", err)
+				return
+			}
+			data, _ := io.ReadAll(f)
+			if string(data) != string(pngHeader) || hdr.Header.Get("Content-Type") != "image/png" || hdr.Filename != "cover.png" {
+				t.Errorf("unexpected part %q %v", data, hdr.Header)
+			}
+			_, _ = w.Write([]byte(`{"result":{"type":"pic","uri":"avatars.yandex.net/get-music-user-playlist/1/%%","custom":true}}`))
+		case "/users/u1/playlists/5/cover/clear":
+			seen[r.URL.Path] = r.Method
+			_, _ = w.Write([]byte(`{"result":"ok"}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	if _, err := c.Playlist.SetVisibility(ctx, "u1", "5", playlist.VisibilityPrivate); err != nil {
+		t.Fatalf("visibility: %v", err)
+	}
+	if _, err := c.Playlist.SetVisibility(ctx, "u1", "5", "friends"); err == nil {
+		t.Fatalf("unknown visibility must fail")
+	}
+	if _, err := c.Playlist.SetDescription(ctx, "u1", "5", "Подборка недели"); err != nil {
+		t.Fatalf("description: %v", err)
+	}
+	if seen["/users/u1/playlists/5/visibility"] != "private" || seen["/users/u1/playlists/5/description"] != "Подборка недели" {
+		t.Fatalf("forms %v", seen)
+	}
+	if _, err := c.Playlist.ClearDescription(ctx, "u1", "5"); err != nil || seen["/users/u1/playlists/5/description"] != "" {
+		t.Fatalf("clear description: %v %v", err, seen)
+	}
+	cover, err := c.Playlist.UploadCover(ctx, "u1", "5", pngHeader)
+	if err != nil || !cover.Result.Custom || cover.Result.Type != "pic" {
+		t.Fatalf("upload cover: %v %+v", err, cover)
+	}
+	if _, err := c.Playlist.UploadCover(ctx, "u1", "5", []byte("not an image")); err == nil {
+		t.Fatalf("non-image must be rejected")
+	}
+	if err := c.Playlist.ClearCover(ctx, "u1", "5"); err != nil || seen["/users/u1/playlists/5/cover/clear"] != http.MethodPost {
+		t.Fatalf("clear cover: %v", err)
+	}
+}
+
+func TestUgcUploadBytesMultipart(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, hdr, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("multipart: %v", err)
+			return
+		}
+		data, _ := io.ReadAll(f)
+		if string(data) != "mp3" || hdr.Filename != "upload.bin" || hdr.Header.Get("Content-Type") != "application/octet-stream" {
+			t.Errorf("unexpected part %q %v", data, hdr.Header)
+		}
+		_, _ = w.Write([]byte(`{"result":"CREATED"}`))
+	}))
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL))
+	res, err := c.Ugc.UploadBytes(context.Background(), &ugc.Upload{PostTarget: srv.URL + "/upload"}, []byte("mp3"))
+	if err != nil || res.Result != "CREATED" {
+		t.Fatalf("upload: %v %+v", err, res)
+	}
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/Banjirome/yandex-music-go/models"
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
+
+// Append добавляет треки в конец плейлиста.
+func (s *PlaylistService) Append(ctx context.Context, pl *playlist.Playlist, tracks []playlist.TrackKey) (*models.Response[playlist.Playlist], error) {
+	if pl == nil {
+		return nil, fmt.Errorf("playlist nil")
+	}
+	return s.InsertAt(ctx, pl, len(pl.Tracks), tracks)
+}
+
+// InsertAt вставляет треки перед позицией index (0..len(pl.Tracks)). Контейнеры
+// без id трека при отправке не учитываются, как и в Diff.
+func (s *PlaylistService) InsertAt(ctx context.Context, pl *playlist.Playlist, index int, tracks []playlist.TrackKey) (*models.Response[playlist.Playlist], error) {
+	if pl == nil {
+		return nil, fmt.Errorf("playlist nil")
+	}
+	if index < 0 || index > len(pl.Tracks) {
+		return nil, fmt.Errorf("insert index %d out of range [0, %d]", index, len(pl.Tracks))
+	}
+	if len(tracks) == 0 {
+		return &models.Response[playlist.Playlist]{Result: *pl}, nil
+	}
+	return s.applyAndGet(ctx, pl, []playlist.ChangeRequest{{Operation: playlist.OpInsert, At: keyPos(pl.Tracks, index), Tracks: tracks}})
+}
+
+// Move перемещает трек с позиции from на позицию to (индексы в текущем pl.Tracks;
+// to — итоговая позиция трека). В API нет операции перемещения, поэтому отправляется
+// пара delete+insert в одном запросе. Трек без id переместить нельзя.
+func (s *PlaylistService) Move(ctx context.Context, pl *playlist.Playlist, from, to int) (*models.Response[playlist.Playlist], error) {
+	if pl == nil {
+		return nil, fmt.Errorf("playlist nil")
+	}
+	n := len(pl.Tracks)
+	if from < 0 || from >= n || to < 0 || to >= n {
+		return nil, fmt.Errorf("move %d -> %d out of range [0, %d)", from, to, n)
+	}
+	if from == to {
+		return &models.Response[playlist.Playlist]{Result: *pl}, nil
+	}
+	k := pl.Tracks[from].Key()
+	if k.Id == "" {
+		return nil, fmt.Errorf("move: track at %d has no id", from)
+	}
+	moved := append(append([]playlist.TrackContainer(nil), pl.Tracks[:from]...), pl.Tracks[from+1:]...)
+	moved = append(moved[:to], append([]playlist.TrackContainer{pl.Tracks[from]}, moved[to:]...)...)
+	key := []playlist.TrackKey{k}
+	at := keyPos(pl.Tracks, from)
+	return s.applyAndGet(ctx, pl, []playlist.ChangeRequest{
+		{Operation: playlist.OpDelete, From: at, To: at + 1, Tracks: key},
+		{Operation: playlist.OpInsert, At: keyPos(moved, to), Tracks: key},
+	})
+}
+
+// keyPos переводит индекс в tracks в позицию среди треков с id — в тех
+// индексах, которыми оперирует change-relative (см. Playlist.Keys).
+func keyPos(tracks []playlist.TrackContainer, i int) int {
+	n := 0
+	for _, tc := range tracks[:i] {
+		if tc.Key().Id != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Reorder упорядочивает треки плейлиста функцией less (стабильно) и отправляет
+// минимальный набор перемещений.
+func (s *PlaylistService) Reorder(ctx context.Context, pl *playlist.Playlist, less func(a, b playlist.TrackContainer) bool) (*models.Response[playlist.Playlist], error) {
+	if pl == nil {
+		return nil, fmt.Errorf("playlist nil")
+	}
+	sorted := append([]playlist.TrackContainer(nil), pl.Tracks...)
+	sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+	desired := make([]playlist.TrackKey, 0, len(sorted))
+	for _, tc := range sorted {
+		if k := tc.Key(); k.Id != "" {
+			desired = append(desired, k)
+		}
+	}
+	return s.Sync(ctx, pl, desired)
+}
+
+// applyAndGet отправляет изменения через change-relative, обновляет pl.Revision
+// и перечитывает плейлист в обход кэша и объединения запросов: ответ, полученный
+// до изменения, вернул бы старое состояние.
+func (s *PlaylistService) applyAndGet(ctx context.Context, pl *playlist.Playlist, changes []playlist.ChangeRequest) (*models.Response[playlist.Playlist], error) {
+	resp, err := s.applyRelative(ctx, pl, changes)
+	if err != nil {
+		return nil, err
+	}
+	pl.Revision = resp.Result.Revision
+	uid := ownerUID(pl)
+	s.c.InvalidateCache(path.Join("users", uid, "playlists", pl.Kind))
+	return s.Get(withoutCoalescing(ctx), uid, pl.Kind)
+}
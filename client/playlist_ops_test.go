@@ -0,0 +1,210 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/cache"
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/models"
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
+
+// playlistServer хранит плейлист u1:5 и применяет change-relative последовательно, как API.
+type playlistServer struct {
+	mu     sync.Mutex
+	t      *testing.T
+	rev    int
+	tracks []string
+	posts  int
+}
+
+func (p *playlistServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/users/u1/playlists/5":
+	case r.Method == http.MethodPost && r.URL.Path == "/users/u1/playlists/5/change-relative":
+		p.posts++
+		_ = r.ParseForm()
+		if r.PostForm.Get("revision") != strconv.Itoa(p.rev) {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		var ops []playlist.ChangeRequest
+		_ = json.Unmarshal([]byte(r.PostForm.Get("diff")), &ops)
+		for _, op := range ops {
+			switch op.Operation {
+			case playlist.OpDelete:
+				p.tracks = append(p.tracks[:op.From], p.tracks[op.To:]...)
+			case playlist.OpInsert:
+				var add []string
+				for _, k := range op.Tracks {
+					add = append(add, k.Id)
+				}
+				p.tracks = append(p.tracks[:op.At], append(add, p.tracks[op.At:]...)...)
+			}
+		}
+		p.rev++
+	default:
+		p.t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(models.Response[playlist.Playlist]{Result: playlist.Playlist{Kind: "5", Revision: p.rev, Owner: &playlist.Owner{Uid: "u1"}, Tracks: containers(p.tracks...)}})
+}
+
+func trackIDs(pl playlist.Playlist) string {
+	var out []string
+	for _, k := range pl.Keys() {
+		out = append(out, k.Id)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestPlaylistPositionalOps(t *testing.T) {
+	ps := &playlistServer{t: t, rev: 1, tracks: []string{"a", "b", "c"}}
+	srv := httptest.NewServer(ps)
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL))
+	ctx := context.Background()
+	pl := playlist.Playlist{Kind: "5", Revision: 1, Owner: &playlist.Owner{Uid: "u1"}, Tracks: containers("a", "b", "c")}
+
+	steps := []struct {
+		name string
+		do   func() (*models.Response[playlist.Playlist], error)
+		want string
+	}{
+		{"append", func() (*models.Response[playlist.Playlist], error) {
+			return c.Playlist.Append(ctx, &pl, []playlist.TrackKey{{Id: "d"}})
+		}, "a b c d"},
+		{"insert", func() (*models.Response[playlist.Playlist], error) {
+			return c.Playlist.InsertAt(ctx, &pl, 1, []playlist.TrackKey{{Id: "x"}, {Id: "y"}})
+		}, "a x y b c d"},
+		{"move down", func() (*models.Response[playlist.Playlist], error) { return c.Playlist.Move(ctx, &pl, 0, 5) }, "x y b c d a"},
+		{"move up", func() (*models.Response[playlist.Playlist], error) { return c.Playlist.Move(ctx, &pl, 4, 1) }, "x d y b c a"},
+		{"reorder", func() (*models.Response[playlist.Playlist], error) {
+			return c.Playlist.Reorder(ctx, &pl, func(a, b playlist.TrackContainer) bool { return a.Track.ID < b.Track.ID })
+		}, "a b c d x y"},
+	}
+	for _, st := range steps {
+		res, err := st.do()
+		if err != nil {
+			t.Fatalf("%s: %v", st.name, err)
+		}
+		if got := trackIDs(res.Result); got != st.want {
+			t.Fatalf("%s: got %q want %q", st.name, got, st.want)
+		}
+		if res.Result.Revision != pl.Revision {
+			t.Fatalf("%s: revision not propagated (%d vs %d)", st.name, res.Result.Revision, pl.Revision)
+		}
+		pl = res.Result
+	}
+	if pl.Revision != 6 {
+		t.Fatalf("expected revision 6, got %d", pl.Revision)
+	}
+}
+
+func TestPlaylistPositionalOpsSkipIDLess(t *testing.T) {
+	ps := &playlistServer{t: t, rev: 1, tracks: []string{"a", "b", "c"}}
+	srv := httptest.NewServer(ps)
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL))
+	ctx := context.Background()
+	// локальная копия содержит контейнер без id, которого нет в позициях API
+	pl := playlist.Playlist{Kind: "5", Revision: 1, Owner: &playlist.Owner{Uid: "u1"},
+		Tracks: append(containers("a"), append([]playlist.TrackContainer{{}}, containers("b", "c")...)...)}
+
+	res, err := c.Playlist.InsertAt(ctx, &pl, 2, []playlist.TrackKey{{Id: "x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := trackIDs(res.Result); got != "a x b c" {
+		t.Fatalf("insert: got %q", got)
+	}
+
+	pl.Tracks = append(containers("a"), append([]playlist.TrackContainer{{}}, containers("x", "b", "c")...)...)
+	pl.Revision = res.Result.Revision
+	res, err = c.Playlist.Move(ctx, &pl, 4, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := trackIDs(res.Result); got != "a c x b" {
+		t.Fatalf("move: got %q", got)
+	}
+	if _, err := c.Playlist.Move(ctx, &pl, 1, 0); err == nil {
+		t.Fatalf("moving an id-less track must fail")
+	}
+}
+
+func TestPlaylistOpsRereadSkipsInflightGet(t *testing.T) {
+	ps := &playlistServer{t: t, rev: 1, tracks: []string{"a", "b"}}
+	started, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		first := false
+		if r.Method == http.MethodGet {
+			once.Do(func() { first = true })
+		}
+		if !first {
+			ps.ServeHTTP(w, r)
+			return
+		}
+		// первый GET фиксирует состояние до изменения и отвечает с задержкой
+		rec := httptest.NewRecorder()
+		ps.ServeHTTP(rec, r)
+		close(started)
+		<-release
+		_, _ = w.Write(rec.Body.Bytes())
+	}))
+	defer srv.Close()
+	timer := time.AfterFunc(5*time.Second, func() { close(release) })
+	c := sdk.New(sdk.WithBaseURL(srv.URL), sdk.WithCoalescing(), sdk.WithCache(cache.NewLRU(100)))
+	ctx := context.Background()
+	pl := playlist.Playlist{Kind: "5", Revision: 1, Owner: &playlist.Owner{Uid: "u1"}, Tracks: containers("a", "b")}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = c.Playlist.Get(ctx, "u1", "5")
+	}()
+	<-started
+	res, err := c.Playlist.Append(ctx, &pl, []playlist.TrackKey{{Id: "c"}})
+	if timer.Stop() {
+		close(release)
+	}
+	<-done
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := trackIDs(res.Result); got != "a b c" {
+		t.Fatalf("re-read joined a stale request: got %q", got)
+	}
+}
+
+func TestPlaylistPositionalOpsValidate(t *testing.T) {
+	ps := &playlistServer{t: t, rev: 1, tracks: []string{"a", "b"}}
+	srv := httptest.NewServer(ps)
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL))
+	ctx := context.Background()
+	pl := playlist.Playlist{Kind: "5", Revision: 1, Owner: &playlist.Owner{Uid: "u1"}, Tracks: containers("a", "b")}
+	if _, err := c.Playlist.InsertAt(ctx, &pl, 3, []playlist.TrackKey{{Id: "x"}}); err == nil {
+		t.Fatalf("insert past end must fail")
+	}
+	if _, err := c.Playlist.Move(ctx, &pl, 0, 2); err == nil {
+		t.Fatalf("move past end must fail")
+	}
+	if _, err := c.Playlist.Move(ctx, &pl, -1, 0); err == nil {
+		t.Fatalf("negative index must fail")
+	}
+	if ps.posts != 0 {
+		t.Fatalf("invalid ops must not reach the server")
+	}
+}
@@ -1,7 +1,6 @@
 package client
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -164,18 +163,15 @@ func (s *PlaylistService) DeleteTracks(ctx context.Context, pl *playlist.Playlis
 		}
 	}
 	changes := make([]playlist.ChangeRequest, 0, len(uniq))
-	// Build index map of playlist current tracks
-	for idx, cont := range pl.Tracks {
+	// операции применяются последовательно: идём с конца, чтобы удаление
+	// не сдвигало индексы следующих
+	for idx := len(pl.Tracks) - 1; idx >= 0; idx-- {
+		cont := pl.Tracks[idx]
 		if cont.Track == nil {
 			continue
 		}
 		if _, ok := uniq[cont.Track.ID]; ok {
-			// construct key
-			key := playlist.TrackKey{Id: cont.Track.ID}
-			if len(cont.Track.Albums) > 0 {
-				key.AlbumId = cont.Track.Albums[0].ID
-			}
-			changes = append(changes, playlist.ChangeRequest{Operation: "delete", From: idx, To: idx + 1, Tracks: []playlist.TrackKey{key}})
+			changes = append(changes, playlist.ChangeRequest{Operation: playlist.OpDelete, From: idx, To: idx + 1, Tracks: []playlist.TrackKey{cont.Key()}})
 		}
 	}
 	if len(changes) == 0 {
@@ -198,12 +194,13 @@ func (s *PlaylistService) Favorites(ctx context.Context, userUID string) (*model
 	return doJSON[wrap](s.c, ctx, http.MethodGet, p, nil, nil)
 }
 
-// GetBatch соответствует POST playlists/list
+// GetBatch соответствует POST playlists/list (пары uid, kind).
+// Большие списки разбиваются на чанки (WithBatching); результат в порядке pairs.
 func (s *PlaylistService) GetBatch(ctx context.Context, pairs [][2]string) (*models.Response[[]playlist.Playlist], error) {
-	ids := make([]string, 0, len(pairs))
-	for _, pr := range pairs {
-		ids = append(ids, pr[0]+":"+pr[1])
-	}
+	return batchResponse(s.GetBatchReport(ctx, pairs))
+}
+
+func (s *PlaylistService) getBatchChunk(ctx context.Context, ids []string) ([]playlist.Playlist, error) {
 	form := url.Values{}
 	form.Set("playlist-ids", strings.Join(ids, ","))
 	resp, err := s.postForm(ctx, "playlists/list", form)
@@ -219,7 +216,7 @@ func (s *PlaylistService) GetBatch(ctx context.Context, pairs [][2]string) (*mod
 	if err := json.Unmarshal(data, &out); err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return out.Result, nil
 }
 
 // applyChanges реализует POST users/{uid}/playlists/{kind}/change с diff.
@@ -241,10 +238,10 @@ func (s *PlaylistService) postFormPlaylist(ctx context.Context, p string, form u
 	defer resp.Body.Close()
 	data, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode >= 400 {
-		// Конфликт ревизии: 409 или тело содержит ключевые слова — возвращаем обычную ошибку (без отдельного sentinel).
+		// Конфликт ревизии: 409 или тело содержит ключевые слова.
 		lower := strings.ToLower(string(data))
 		if resp.StatusCode == http.StatusConflict || (strings.Contains(lower, "revision") && strings.Contains(lower, "conflict")) {
-			return nil, fmt.Errorf("playlist revision conflict")
+			return nil, ErrRevisionConflict
 		}
 		return nil, fmt.Errorf("playlist op failed: %s", resp.Status)
 	}
@@ -256,14 +253,11 @@ func (s *PlaylistService) postFormPlaylist(ctx context.Context, p string, form u
 }
 
 func (s *PlaylistService) postForm(ctx context.Context, p string, form url.Values) (*http.Response, error) {
-	req, err := s.c.newRequest(ctx, http.MethodPost, p, nil, nil)
+	req, err := s.c.newRequest(ctx, http.MethodPost, p, nil, form)
 	if err != nil {
 		return nil, err
 	}
-	body := form.Encode()
-	req.Body = io.NopCloser(bytes.NewBufferString(body))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	return s.c.http.Do(req)
+	return s.c.do(req)
 }
 
 // join helper (пока не используется)
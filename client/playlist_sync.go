@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/Banjirome/yandex-music-go/models"
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
+
+// Sync приводит плейлист к списку desired одним запросом change-relative: по текущим
+// трекам pl и его ревизии строится минимальный diff (playlist.Diff). pl должен быть
+// получен с треками (Get). Возвращает плейлист после изменения; pl.Revision обновляется.
+func (s *PlaylistService) Sync(ctx context.Context, pl *playlist.Playlist, desired []playlist.TrackKey) (*models.Response[playlist.Playlist], error) {
+	if pl == nil {
+		return nil, fmt.Errorf("playlist nil")
+	}
+	changes := playlist.Diff(pl.Keys(), desired)
+	if len(changes) == 0 {
+		return &models.Response[playlist.Playlist]{Result: *pl}, nil
+	}
+	return s.applyAndGet(ctx, pl, changes)
+}
+
+// applyRelative POST users/{uid}/playlists/{kind}/change-relative: операции применяются
+// последовательно к ревизии pl.Revision.
+func (s *PlaylistService) applyRelative(ctx context.Context, pl *playlist.Playlist, changes []playlist.ChangeRequest) (*models.Response[playlist.Playlist], error) {
+	diffJSON, err := json.Marshal(changes)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("kind", pl.Kind)
+	form.Set("revision", strconv.Itoa(pl.Revision))
+	form.Set("diff", string(diffJSON))
+	return s.postFormPlaylist(ctx, path.Join("users", ownerUID(pl), "playlists", pl.Kind, "change-relative"), form)
+}
+
+// ownerUID uid владельца плейлиста (Owner, либо поле uid).
+func ownerUID(pl *playlist.Playlist) string {
+	if pl.Owner != nil && pl.Owner.Uid != "" {
+		return pl.Owner.Uid
+	}
+	return pl.Uid
+}
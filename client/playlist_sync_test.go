@@ -0,0 +1,82 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/models"
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
+
+func containers(ids ...string) []playlist.TrackContainer {
+	out := make([]playlist.TrackContainer, len(ids))
+	for i, id := range ids {
+		out[i] = playlist.TrackContainer{ID: id, Track: &playlist.Track{ID: id}}
+	}
+	return out
+}
+
+func TestPlaylistSync(t *testing.T) {
+	var diff []map[string]any
+	posted := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/users/u1/playlists/5/change-relative":
+			_ = r.ParseForm()
+			if r.PostForm.Get("revision") != "3" {
+				t.Errorf("revision %q", r.PostForm.Get("revision"))
+			}
+			if err := json.Unmarshal([]byte(r.PostForm.Get("diff")), &diff); err != nil {
+				t.Errorf("diff: %v", err)
+			}
+			posted = true
+			_ = json.NewEncoder(w).Encode(models.Response[playlist.Playlist]{Result: playlist.Playlist{Kind: "5", Revision: 4}})
+		case r.Method == http.MethodGet && r.URL.Path == "/users/u1/playlists/5":
+			_ = json.NewEncoder(w).Encode(models.Response[playlist.Playlist]{Result: playlist.Playlist{Kind: "5", Revision: 4, Tracks: containers("c", "a", "d")}})
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL))
+	pl := playlist.Playlist{Kind: "5", Revision: 3, Owner: &playlist.Owner{Uid: "u1"}, Tracks: containers("a", "b", "d")}
+
+	res, err := c.Playlist.Sync(context.Background(), &pl, []playlist.TrackKey{{Id: "c"}, {Id: "a"}, {Id: "d"}})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if !posted || pl.Revision != 4 || len(res.Result.Tracks) != 3 {
+		t.Fatalf("unexpected result %+v (pl rev %d)", res.Result, pl.Revision)
+	}
+	// delete b (1..2), затем insert c at 0
+	if len(diff) != 2 || diff[0]["operation"] != "delete" || diff[0]["from"] != 1.0 || diff[1]["operation"] != "insert" || diff[1]["at"] != 0.0 {
+		t.Fatalf("diff %+v", diff)
+	}
+
+	posted = false
+	if _, err := c.Playlist.Sync(context.Background(), &res.Result, res.Result.Keys()); err != nil || posted {
+		t.Fatalf("no-op sync must not post: %v", err)
+	}
+}
+
+func TestPlaylistDeleteTracksDescending(t *testing.T) {
+	var diff []playlist.ChangeRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		_ = json.Unmarshal([]byte(r.PostForm.Get("diff")), &diff)
+		_ = json.NewEncoder(w).Encode(models.Response[playlist.Playlist]{Result: playlist.Playlist{Revision: 2}})
+	}))
+	defer srv.Close()
+	c := sdk.New(sdk.WithBaseURL(srv.URL))
+	pl := playlist.Playlist{Kind: "5", Revision: 1, Owner: &playlist.Owner{Uid: "u1"}, Tracks: containers("a", "b", "c")}
+	if _, err := c.Playlist.DeleteTracks(context.Background(), &pl, []playlist.Track{{ID: "a"}, {ID: "c"}}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(diff) != 2 || diff[0].From != 2 || diff[1].From != 0 {
+		t.Fatalf("deletes must go bottom-up: %+v", diff)
+	}
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+)
+
+var (
+	// ErrEmptyPool возвращается, если в пуле нет аккаунтов.
+	ErrEmptyPool = errors.New("account pool is empty")
+	// ErrAccountNotFound возвращается, если аккаунт с указанным uid не найден.
+	ErrAccountNotFound = errors.New("account not found")
+)
+
+// AccountPool управляет несколькими аккаунтами: у каждого свой Client со своими
+// auth.Storage и cookie jar, а HTTP transport и RateLimiter общие.
+// Методы пула безопасны для конкурентного использования.
+type AccountPool struct {
+	base      []Option
+	transport http.RoundTripper
+	timeout   time.Duration
+
+	mu      sync.RWMutex
+	clients []*Client
+	next    atomic.Uint64
+}
+
+// NewAccountPool создаёт пул; opts применяются к каждому аккаунту (BaseURL, credentials, WithRateLimiter...).
+// Transport и Timeout берутся из WithHTTPClient, jar из него не используется.
+func NewAccountPool(opts ...Option) *AccountPool {
+	cfg := defaultConfig()
+	for _, o := range opts {
+		o(&cfg)
+	}
+	p := &AccountPool{base: opts, timeout: 15 * time.Second}
+	if cfg.HTTPClient != nil {
+		p.transport, p.timeout = cfg.HTTPClient.Transport, cfg.HTTPClient.Timeout
+	}
+	if p.transport == nil {
+		p.transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	if rt, ok := buildTransport(p.transport, &cfg, nil); ok {
+		p.transport = rt
+	}
+	return p
+}
+
+// Add добавляет аккаунт с готовым хранилищем; opts дополняют общие опции пула.
+func (p *AccountPool) Add(st *auth.Storage, opts ...Option) *Client {
+	c := p.newClient(st, opts)
+	p.insert(c)
+	return c
+}
+
+// AddToken добавляет аккаунт по OAuth токену, предварительно проверив его (Authorize заполняет uid).
+func (p *AccountPool) AddToken(ctx context.Context, token string, opts ...Option) (*Client, error) {
+	c := p.newClient(auth.New(""), opts)
+	if err := c.User.Authorize(ctx, token); err != nil {
+		return nil, err
+	}
+	p.insert(c)
+	return c, nil
+}
+
+func (p *AccountPool) newClient(st *auth.Storage, opts []Option) *Client {
+	all := make([]Option, 0, len(p.base)+len(opts)+2)
+	all = append(all, p.base...)
+	all = append(all,
+		WithHTTPClient(&http.Client{Transport: p.transport, Timeout: p.timeout}),
+		WithAuthStorage(st),
+	)
+	return New(append(all, opts...)...)
+}
+
+func (p *AccountPool) insert(c *Client) {
+	p.mu.Lock()
+	p.clients = append(p.clients, c)
+	p.mu.Unlock()
+}
+
+// Remove удаляет аккаунт по uid.
+func (p *AccountPool) Remove(uid string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, c := range p.clients {
+		if c.UID() == uid {
+			p.clients = append(p.clients[:i], p.clients[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Get возвращает клиент аккаунта с указанным uid.
+func (p *AccountPool) Get(uid string) (*Client, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, c := range p.clients {
+		if c.UID() == uid {
+			return c, nil
+		}
+	}
+	return nil, ErrAccountNotFound
+}
+
+// Next возвращает следующий аккаунт по кругу (round-robin).
+func (p *AccountPool) Next() (*Client, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if len(p.clients) == 0 {
+		return nil, ErrEmptyPool
+	}
+	i := (p.next.Add(1) - 1) % uint64(len(p.clients))
+	return p.clients[i], nil
+}
+
+// Pick выбирает аккаунт для операции: по uid, а при пустом uid — round-robin.
+func (p *AccountPool) Pick(uid string) (*Client, error) {
+	if uid == "" {
+		return p.Next()
+	}
+	return p.Get(uid)
+}
+
+// Do выполняет fn с аккаунтом, выбранным Pick.
+func (p *AccountPool) Do(ctx context.Context, uid string, fn func(ctx context.Context, c *Client) error) error {
+	c, err := p.Pick(uid)
+	if err != nil {
+		return err
+	}
+	return fn(ctx, c)
+}
+
+// Clients возвращает снимок списка клиентов пула.
+func (p *AccountPool) Clients() []*Client {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]*Client(nil), p.clients...)
+}
+
+// Len количество аккаунтов в пуле.
+func (p *AccountPool) Len() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.clients)
+}
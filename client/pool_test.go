@@ -0,0 +1,101 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/models"
+)
+
+type countingLimiter struct{ n atomic.Int64 }
+
+func (l *countingLimiter) Wait(context.Context) error {
+	l.n.Add(1)
+	return nil
+}
+
+// TestAccountPool два аккаунта: uid из своего хранилища, отдельные cookie, общий limiter.
+func TestAccountPool(t *testing.T) {
+	var mu sync.Mutex
+	played := map[string]string{} // uid -> token
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "OAuth ")
+		switch r.URL.Path {
+		case "/account/status":
+			http.SetCookie(w, &http.Cookie{Name: "acc", Value: token, Path: "/"})
+			res := models.Response[sdk.UserAuthResult]{}
+			res.Result.Account.Uid = "uid-" + token
+			_ = json.NewEncoder(w).Encode(res)
+		case "/play-audio":
+			_ = r.ParseForm()
+			if c, err := r.Cookie("acc"); err != nil || c.Value != token {
+				t.Errorf("cookie of another account: %v %v", c, err)
+			}
+			mu.Lock()
+			played[r.PostForm.Get("uid")] = token
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"result":"ok"}`))
+		default:
+			t.Errorf("unexpected %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	rl := &countingLimiter{}
+	pool := sdk.NewAccountPool(sdk.WithBaseURL(srv.URL), sdk.WithRateLimiter(rl))
+	ctx := context.Background()
+	if _, err := pool.Next(); !errors.Is(err, sdk.ErrEmptyPool) {
+		t.Fatalf("expected ErrEmptyPool, got %v", err)
+	}
+	for _, tok := range []string{"a", "b"} {
+		if _, err := pool.AddToken(ctx, tok); err != nil {
+			t.Fatalf("add %s: %v", tok, err)
+		}
+	}
+	if c, err := pool.Get("uid-b"); err != nil || c.UID() != "uid-b" {
+		t.Fatalf("get by uid: %v", err)
+	}
+	if _, err := pool.Get("uid-x"); !errors.Is(err, sdk.ErrAccountNotFound) {
+		t.Fatalf("expected ErrAccountNotFound, got %v", err)
+	}
+
+	// round-robin чередует аккаунты
+	first, _ := pool.Next()
+	second, _ := pool.Next()
+	third, _ := pool.Next()
+	if first == second || first != third {
+		t.Fatalf("round-robin order broken")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := pool.Do(ctx, "", func(ctx context.Context, c *sdk.Client) error {
+				return c.Track.SendPlayInfo(ctx, sdk.Track{ID: "1"}, "feed", "", "", false, 1, 1)
+			})
+			if err != nil {
+				t.Errorf("play: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if played["uid-a"] != "a" || played["uid-b"] != "b" {
+		t.Fatalf("uid/token mixed up: %v", played)
+	}
+	if got := rl.n.Load(); got != 2+8 {
+		t.Fatalf("limiter waits %d", got)
+	}
+	if !pool.Remove("uid-a") || pool.Len() != 1 {
+		t.Fatalf("remove failed")
+	}
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+	"github.com/Banjirome/yandex-music-go/models"
+)
+
+// TerminalQR печатает QR-код входа и ход его подтверждения в w (обычно os.Stdout).
+// Подходит для входа на headless-сервере: код сканируется приложением на телефоне.
+// Реализует ShowQR и QRStatusHandler, поэтому его можно встроить в ChallengeHandler для Login.
+type TerminalQR struct {
+	W io.Writer
+	// Invert закрашивает тёмные модули вместо светлых (светлая тема терминала).
+	Invert bool
+
+	last string
+}
+
+// ShowQR рисует QR-код и ссылку под ним.
+func (t *TerminalQR) ShowQR(_ context.Context, link string) error {
+	t.last = ""
+	if err := auth.WriteQRTerminal(t.W, link, t.Invert); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(t.W, "%s\nОтсканируйте код в приложении Яндекса или откройте ссылку.\n", link)
+	return err
+}
+
+// QRStatus печатает статус опроса, только когда он меняется.
+func (t *TerminalQR) QRStatus(_ context.Context, st *models.AuthQRStatus) {
+	line := qrStatusText(st)
+	if line == t.last {
+		return
+	}
+	t.last = line
+	fmt.Fprintln(t.W, line)
+}
+
+func qrStatusText(st *models.AuthQRStatus) string {
+	switch {
+	case strings.ToLower(st.Status) == "ok" && st.MagicLinkConfirmed:
+		return "Вход подтверждён."
+	case strings.ToLower(st.Status) == "ok":
+		return "Ожидание подтверждения…"
+	default:
+		return "Статус: " + st.Status
+	}
+}
+
+// AuthorizeByQRTerminal получает ссылку входа, рисует её QR-кодом в w и ждёт подтверждения,
+// печатая изменения статуса. Пустые pollInterval/maxWait заменяются значениями Login.
+func (s *UserService) AuthorizeByQRTerminal(ctx context.Context, w io.Writer, invert bool, pollInterval, maxWait time.Duration) (*models.AuthQRStatus, error) {
+	if pollInterval <= 0 {
+		pollInterval = loginPollInterval
+	}
+	if maxWait <= 0 {
+		maxWait = loginConfirmWait
+	}
+	link, err := s.GetAuthQRLink(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t := &TerminalQR{W: w, Invert: invert}
+	if err := t.ShowQR(ctx, link); err != nil {
+		return nil, err
+	}
+	return s.AuthorizeByQRWithStatus(ctx, pollInterval, maxWait, func(st *models.AuthQRStatus) { t.QRStatus(ctx, st) })
+}
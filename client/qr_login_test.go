@@ -0,0 +1,48 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+)
+
+func TestAuthorizeByQRTerminal(t *testing.T) {
+	calls := map[string]int{}
+	base := passportHandler(t, nil, calls)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// первые два опроса — код ещё не отсканирован
+		if r.URL.Path == "/auth/new/magic/status/" && calls[r.URL.Path] < 2 {
+			calls[r.URL.Path]++
+			_, _ = w.Write([]byte(`{"status":"ok","magic_link_confirmed":false}`))
+			return
+		}
+		base(w, r)
+	}))
+	defer srv.Close()
+	st := auth.New("")
+	var out bytes.Buffer
+	res, err := newLoginClient(srv, st).User.AuthorizeByQRTerminal(context.Background(), &out, false, time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatalf("qr: %v", err)
+	}
+	if !res.MagicLinkConfirmed || calls["/auth/new/magic/status/"] != 3 {
+		t.Fatalf("unexpected status %+v calls %v", res, calls)
+	}
+	s := out.String()
+	if !strings.Contains(s, "█") || !strings.Contains(s, "track_id=qr-track") {
+		t.Fatalf("qr not rendered:\n%s", s)
+	}
+	// повторяющийся статус печатается один раз
+	if strings.Count(s, "Ожидание подтверждения") != 1 || !strings.Contains(s, "Вход подтверждён") {
+		t.Fatalf("status lines:\n%s", s)
+	}
+	if acc := st.GetAccessToken(); acc == nil || acc.AccessToken != "x-token" {
+		t.Fatalf("x-token not stored %+v", acc)
+	}
+}
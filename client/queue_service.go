@@ -26,7 +26,7 @@ func (s *QueueService) List(ctx context.Context, device string) (*models.Respons
 	if device != "" {
 		req.Header.Set("X-Yandex-Music-Device", device)
 	}
-	resp, err := s.c.http.Do(req)
+	resp, err := s.c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +56,7 @@ func (s *QueueService) Create(ctx context.Context, q *queue.Queue, device string
 	if device != "" {
 		req.Header.Set("X-Yandex-Music-Device", device)
 	}
-	resp, err := s.c.http.Do(req)
+	resp, err := s.c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +85,7 @@ func (s *QueueService) UpdatePosition(ctx context.Context, queueID string, curre
 	if device != "" {
 		req.Header.Set("X-Yandex-Music-Device", device)
 	}
-	resp, err := s.c.http.Do(req)
+	resp, err := s.c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +60,7 @@ func (s *RadioService) Feedback(ctx context.Context, station radio.Station, fbTy
 	if err != nil {
 		return err
 	}
-	resp, err := s.c.http.Do(req)
+	resp, err := s.c.do(req)
 	if err != nil {
 		return err
 	}
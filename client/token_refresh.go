@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// do выполняет API запрос через coalescing и кеш (если они включены).
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	next := c.fetch
+	if c.cfg.Cache != nil {
+		next = c.doCached
+	}
+	if c.cfg.Coalesce {
+		return c.doCoalesced(req, next)
+	}
+	return next(req)
+}
+
+// fetch выполняет API запрос: заранее обновляет истекающий токен и один раз
+// повторяет запрос после 401, если токен удалось обновить.
+func (c *Client) fetch(req *http.Request) (*http.Response, error) {
+	refreshed := false
+	if c.canRefresh() && c.auth.TokenExpiresWithin(c.cfg.RefreshSkew) {
+		if err := c.refreshToken(req.Context(), tokenFromHeader(req)); err == nil {
+			c.setAuthHeader(req)
+			refreshed = true
+		}
+	}
+	resp, err := c.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || refreshed || !c.canRefresh() {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil // тело нельзя перечитать — повтор невозможен
+	}
+	if err := c.refreshToken(req.Context(), tokenFromHeader(req)); err != nil {
+		return resp, nil
+	}
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	resp.Body.Close()
+	c.setAuthHeader(retry)
+	return c.send(retry)
+}
+
+// send отправляет запрос с учётом RateLimiter.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if rl := c.cfg.RateLimiter; rl != nil {
+		if err := rl.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	return c.http.Do(req)
+}
+
+// canRefresh: обновление возможно при наличии x-token и client credentials.
+func (c *Client) canRefresh() bool {
+	acc := c.auth.GetAccessToken()
+	return acc != nil && acc.AccessToken != "" && c.cfg.ClientID != ""
+}
+
+// refreshToken обменивает x-token на новый music token через GetAccessToken.
+// stale — токен, с которым был сделан запрос: если его уже заменили, повторный обмен не нужен.
+func (c *Client) refreshToken(ctx context.Context, stale string) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	if c.auth.GetToken() != stale && !c.auth.TokenExpiresWithin(c.cfg.RefreshSkew) {
+		return nil
+	}
+	_, err := c.User.GetAccessToken(ctx)
+	return err
+}
+
+func (c *Client) setAuthHeader(req *http.Request) {
+	if token := c.auth.GetToken(); token != "" {
+		req.Header.Set("Authorization", "OAuth "+token)
+	}
+}
+
+func tokenFromHeader(req *http.Request) string {
+	const prefix = "OAuth "
+	h := req.Header.Get("Authorization")
+	if len(h) > len(prefix) {
+		return h[len(prefix):]
+	}
+	return ""
+}
@@ -0,0 +1,144 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	"github.com/Banjirome/yandex-music-go/models"
+)
+
+// rewriteTransport направляет все запросы (включая oauth.yandex.ru) на тестовый сервер.
+type rewriteTransport struct{ target *url.URL }
+
+func (rt rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme, r.URL.Host = rt.target.Scheme, rt.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func newRefreshServer(t *testing.T, exchanges *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/1/token":
+			if err := r.ParseForm(); err != nil {
+				t.Fatalf("parse form: %v", err)
+			}
+			if r.PostForm.Get("access_token") != "x-token" || r.PostForm.Get("client_id") != "cid" {
+				t.Fatalf("unexpected exchange form %v", r.PostForm)
+			}
+			atomic.AddInt32(exchanges, 1)
+			_, _ = w.Write([]byte(`{"access_token":"new","token_type":"bearer","expires_in":3600}`))
+		case "/feed":
+			if r.Header.Get("Authorization") != "OAuth new" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(models.Response[map[string]any]{Result: map[string]any{"ok": true}})
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+}
+
+func newRefreshClient(srv *httptest.Server, st *auth.Storage, opts ...sdk.Option) *sdk.Client {
+	u, _ := url.Parse(srv.URL)
+	opts = append([]sdk.Option{
+		sdk.WithBaseURL(srv.URL),
+		sdk.WithHTTPClient(&http.Client{Transport: rewriteTransport{target: u}}),
+		sdk.WithAuthStorage(st),
+		sdk.WithClientCredentials("cid", "secret"),
+	}, opts...)
+	return sdk.New(opts...)
+}
+
+func TestRetryAfterUnauthorized(t *testing.T) {
+	var exchanges int32
+	srv := newRefreshServer(t, &exchanges)
+	defer srv.Close()
+
+	st := auth.New("old")
+	st.SetAccessToken(&auth.AccessToken{AccessToken: "x-token"})
+	var hooked *auth.AccessToken
+	c := newRefreshClient(srv, st, sdk.WithOnTokenRefreshed(func(tok *auth.AccessToken) { hooked = tok }))
+
+	if _, err := c.Landing.Feed(context.Background()); err != nil {
+		t.Fatalf("feed: %v", err)
+	}
+	if exchanges != 1 {
+		t.Fatalf("expected 1 token exchange got %d", exchanges)
+	}
+	cr := st.Credentials()
+	if cr.Token != "new" || hooked == nil || hooked.AccessToken != "new" {
+		t.Fatalf("token not refreshed: %s %+v", cr.Token, hooked)
+	}
+	if d := time.Until(cr.ExpiresAt); d < 59*time.Minute || d > time.Hour {
+		t.Fatalf("unexpected expiry %v", cr.ExpiresAt)
+	}
+}
+
+func TestProactiveRefresh(t *testing.T) {
+	var exchanges int32
+	srv := newRefreshServer(t, &exchanges)
+	defer srv.Close()
+
+	st := auth.New("old")
+	st.SetAccessToken(&auth.AccessToken{AccessToken: "x-token"})
+	st.SetToken("old", time.Now().Add(10*time.Second))
+	c := newRefreshClient(srv, st)
+	for i := 0; i < 2; i++ {
+		if _, err := c.Landing.Feed(context.Background()); err != nil {
+			t.Fatalf("feed: %v", err)
+		}
+	}
+	if exchanges != 1 {
+		t.Fatalf("expected single proactive exchange got %d", exchanges)
+	}
+}
+
+func TestNoRefreshWithoutXToken(t *testing.T) {
+	var exchanges int32
+	srv := newRefreshServer(t, &exchanges)
+	defer srv.Close()
+	c := newRefreshClient(srv, auth.New("old"))
+	_, err := c.Landing.Feed(context.Background())
+	if apiErr, ok := err.(*models.APIError); !ok || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 api error got %v", err)
+	}
+	if exchanges != 0 {
+		t.Fatalf("unexpected exchange")
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Load() (*auth.Snapshot, error) { return nil, nil }
+func (failingStore) Save(*auth.Snapshot) error     { return errors.New("disk full") }
+
+func TestRefreshSurvivesStoreSaveError(t *testing.T) {
+	var exchanges int32
+	srv := newRefreshServer(t, &exchanges)
+	defer srv.Close()
+
+	st := auth.New("old")
+	st.SetAccessToken(&auth.AccessToken{AccessToken: "x-token"})
+	c := newRefreshClient(srv, st, sdk.WithStore(failingStore{}))
+	if _, err := c.Landing.Feed(context.Background()); err != nil {
+		t.Fatalf("feed: %v", err)
+	}
+	if exchanges != 1 || st.Credentials().Token != "new" {
+		t.Fatalf("exchanges=%d token=%s", exchanges, st.Credentials().Token)
+	}
+	if err := c.SaveError(); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("save error %v", err)
+	}
+}
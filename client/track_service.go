@@ -1,7 +1,6 @@
 package client
 
 import (
-	"bytes"
 	"context"
 	"crypto/md5"
 	"crypto/sha1"
@@ -26,12 +25,18 @@ import (
 type Track struct {
 	ID         string   `json:"id"`
 	Title      string   `json:"title"`
+	Version    string   `json:"version,omitempty"`
 	DurationMs int64    `json:"durationMs"`
 	Albums     []Album  `json:"albums,omitempty"`
 	Artists    []Artist `json:"artists,omitempty"`
 	Position   *int     `json:"position,omitempty"`
+	// Available false — трек изъят из каталога (nil — сервер не прислал поле).
+	Available *bool `json:"available,omitempty"`
 }
 
+// IsAvailable сообщает, можно ли слушать трек (отсутствие поля считается доступностью).
+func (t Track) IsAvailable() bool { return t.Available == nil || *t.Available }
+
 type Album struct {
 	ID    string `json:"id"`
 	Title string `json:"title"`
@@ -68,8 +73,13 @@ type TrackSimilar struct {
 // TrackService mirrors C# YTrackAPI.
 type TrackService struct{ c *Client }
 
-// Get one or many tracks (POST /tracks form: track-ids, with-positions=true)
+// Get one or many tracks (POST /tracks form: track-ids, with-positions=true).
+// Большие списки разбиваются на чанки (WithBatching); результат в порядке ids.
 func (s *TrackService) Get(ctx context.Context, ids ...string) (*models.Response[[]Track], error) {
+	return batchResponse(s.GetReport(ctx, ids...))
+}
+
+func (s *TrackService) getChunk(ctx context.Context, ids []string) ([]Track, error) {
 	form := url.Values{}
 	form.Set("track-ids", strings.Join(ids, ","))
 	form.Set("with-positions", "true")
@@ -86,7 +96,7 @@ func (s *TrackService) Get(ctx context.Context, ids ...string) (*models.Response
 	if err := json.Unmarshal(data, &out); err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return out.Result, nil
 }
 
 // Metadata for download: GET tracks/{trackKey}/download-info?direct=<bool>
@@ -206,10 +216,11 @@ func (s *TrackService) SendPlayInfo(ctx context.Context, track Track, from, play
 		playID = fmt.Sprintf("%d-%d-%d", rand.Intn(1000), rand.Intn(1000), rand.Intn(1000))
 	}
 	form.Set("play_id", playID)
-	if s.c.auth == nil || s.c.auth.User == nil || s.c.auth.User.Uid == "" {
+	uid := s.c.UID()
+	if uid == "" {
 		return errors.New("send play info: unauthorized (missing user uid)")
 	}
-	form.Set("uid", s.c.auth.User.Uid)
+	form.Set("uid", uid)
 	now := time.Now().UTC().Format(time.RFC3339Nano)
 	form.Set("timestamp", now)
 	form.Set("client-now", now)
@@ -235,14 +246,11 @@ func (s *TrackService) SendPlayInfo(ctx context.Context, track Track, from, play
 
 // Helpers
 func (s *TrackService) postForm(ctx context.Context, p string, form url.Values) (*http.Response, error) {
-	req, err := s.c.newRequest(ctx, http.MethodPost, p, nil, nil)
+	req, err := s.c.newRequest(ctx, http.MethodPost, p, nil, form)
 	if err != nil {
 		return nil, err
 	}
-	encoded := form.Encode()
-	req.Body = io.NopCloser(bytes.NewBufferString(encoded))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	return s.c.http.Do(req)
+	return s.c.do(req)
 }
 
 // ExtractData downloads full binary content for provided trackKey using best FileLink selection.
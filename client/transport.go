@@ -0,0 +1,123 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Route направление исходящих запросов для выбора прокси / transport.
+type Route string
+
+const (
+	// RouteAPI запросы к API (BaseURL).
+	RouteAPI Route = "api"
+	// RouteStorage скачивание и загрузка медиа (storage/CDN хосты, ugc upload) — всё, что не попало в другие направления.
+	RouteStorage Route = "storage"
+	// RoutePassport passport, oauth, login и mobileproxy (MobileProxyBaseURL).
+	RoutePassport Route = "passport"
+	// RouteYnison websocket соединения Ynison.
+	RouteYnison Route = "ynison"
+)
+
+var routes = []Route{RouteAPI, RouteStorage, RoutePassport, RouteYnison}
+
+// хосты авторизации, обслуживаемые RoutePassport
+var passportHosts = []string{"passport.yandex.ru", "mobileproxy.passport.yandex.net", "oauth.yandex.ru", "login.yandex.ru"}
+
+// routeTransport выбирает RoundTripper по хосту запроса.
+type routeTransport struct {
+	byRoute map[Route]http.RoundTripper
+	apiHost string
+	auth    map[string]bool
+}
+
+func (t *routeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.byRoute[t.route(req.URL)].RoundTrip(req)
+}
+
+func (t *routeTransport) route(u *url.URL) Route {
+	host := u.Hostname()
+	switch {
+	case host == t.apiHost:
+		return RouteAPI
+	case t.auth[host]:
+		return RoutePassport
+	case strings.Contains(host, "ynison"):
+		return RouteYnison
+	default:
+		return RouteStorage
+	}
+}
+
+// buildTransport собирает маршрутизирующий transport из Config.Proxies, Config.Transports и
+// proxy хранилища (используется для направлений без явной настройки).
+// Возвращает false, если ничего не настроено и base нужно оставить как есть.
+func buildTransport(base http.RoundTripper, cfg *Config, storageProxy *url.URL) (http.RoundTripper, bool) {
+	if len(cfg.Proxies) == 0 && len(cfg.Transports) == 0 && storageProxy == nil {
+		return base, false
+	}
+	if _, ok := base.(*routeTransport); ok && storageProxy == nil {
+		return base, false // уже собран (общий transport AccountPool)
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &routeTransport{byRoute: map[Route]http.RoundTripper{}, auth: map[string]bool{}}
+	if u, err := url.Parse(cfg.BaseURL); err == nil {
+		t.apiHost = u.Hostname()
+	}
+	for _, h := range passportHosts {
+		t.auth[h] = true
+	}
+	if u, err := url.Parse(cfg.MobileProxyBaseURL); err == nil && u.Host != "" {
+		t.auth[u.Hostname()] = true
+	}
+	shared := map[string]http.RoundTripper{} // один transport на каждый прокси
+	for _, r := range routes {
+		if rt := cfg.Transports[r]; rt != nil {
+			t.byRoute[r] = rt
+			continue
+		}
+		proxy := cfg.Proxies[r]
+		if proxy == nil {
+			proxy = cfg.Proxies[""]
+		}
+		if proxy == nil {
+			proxy = storageProxy
+		}
+		if proxy == nil {
+			t.byRoute[r] = base
+			continue
+		}
+		if rt, ok := shared[proxy.String()]; ok {
+			t.byRoute[r] = rt
+			continue
+		}
+		t.byRoute[r] = proxiedTransport(base, proxy)
+		shared[proxy.String()] = t.byRoute[r]
+	}
+	return t, true
+}
+
+// proxiedTransport клонирует base (или http.DefaultTransport, если base не *http.Transport)
+// с заданным прокси. Схемы http/https используют CONNECT, socks5/socks5h — SOCKS5.
+func proxiedTransport(base http.RoundTripper, proxy *url.URL) http.RoundTripper {
+	ht, ok := base.(*http.Transport)
+	if !ok {
+		ht = http.DefaultTransport.(*http.Transport)
+	}
+	ht = ht.Clone()
+	ht.Proxy = http.ProxyURL(proxy)
+	return ht
+}
+
+// routeClient возвращает http.Client для направления r (для соединений вне c.http, например websocket).
+// nil — использовать клиент по умолчанию.
+func (c *Client) routeClient(r Route) *http.Client {
+	rt, ok := c.http.Transport.(*routeTransport)
+	if !ok {
+		return nil
+	}
+	return &http.Client{Transport: rt.byRoute[r], Jar: c.http.Jar}
+}
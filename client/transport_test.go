@@ -0,0 +1,184 @@
+package client_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	fake "github.com/Banjirome/yandex-music-go/ymtest/ynison"
+)
+
+// recordingProxy HTTP прокси, запоминающий хосты запросов; отвечает сам (CONNECT отклоняет).
+type recordingProxy struct {
+	*httptest.Server
+	mu    sync.Mutex
+	hosts []string
+}
+
+func newRecordingProxy() *recordingProxy {
+	p := &recordingProxy{}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		p.hosts = append(p.hosts, r.Method+" "+r.Host)
+		p.mu.Unlock()
+		if r.Method == http.MethodConnect {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":{}}`))
+	}))
+	return p
+}
+
+func (p *recordingProxy) URL() *url.URL {
+	u, _ := url.Parse(p.Server.URL)
+	return u
+}
+
+func (p *recordingProxy) Hosts() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.hosts...)
+}
+
+func TestRouteProxies(t *testing.T) {
+	api, media, login := newRecordingProxy(), newRecordingProxy(), newRecordingProxy()
+	defer api.Close()
+	defer media.Close()
+	defer login.Close()
+	c := sdk.New(
+		sdk.WithBaseURL("http://api.test/"),
+		sdk.WithRouteProxy(sdk.RouteAPI, api.URL()),
+		sdk.WithRouteProxy(sdk.RouteStorage, media.URL()),
+		sdk.WithRouteProxy(sdk.RoutePassport, login.URL()),
+	)
+	ctx := context.Background()
+	if _, err := c.Landing.Feed(ctx); err != nil {
+		t.Fatalf("feed: %v", err)
+	}
+	if _, err := c.Downloader.Bytes(ctx, "http://cdn.test/track.mp3"); err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	_, _ = c.User.GetAuthQRLink(ctx) // CONNECT отклоняется прокси
+	if h := api.Hosts(); len(h) != 1 || h[0] != "GET api.test" {
+		t.Fatalf("api proxy saw %v", h)
+	}
+	if h := media.Hosts(); len(h) != 1 || h[0] != "GET cdn.test" {
+		t.Fatalf("storage proxy saw %v", h)
+	}
+	if h := login.Hosts(); len(h) != 1 || h[0] != "CONNECT passport.yandex.ru:443" {
+		t.Fatalf("passport proxy saw %v", h)
+	}
+}
+
+func TestStorageProxyFallback(t *testing.T) {
+	p := newRecordingProxy()
+	defer p.Close()
+	st := auth.New("tok")
+	st.SetProxy(p.URL())
+	c := sdk.New(sdk.WithBaseURL("http://api.test/"), sdk.WithAuthStorage(st))
+	if _, err := c.Landing.Feed(context.Background()); err != nil {
+		t.Fatalf("feed: %v", err)
+	}
+	if h := p.Hosts(); len(h) != 1 {
+		t.Fatalf("storage proxy not used: %v", h)
+	}
+}
+
+// startSocks5 минимальный SOCKS5 сервер (без аутентификации, только CONNECT).
+func startSocks5(t *testing.T) (*url.URL, func() int) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	var mu sync.Mutex
+	dials := 0
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 262)
+				// greeting: VER NMETHODS METHODS
+				if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+					return
+				}
+				if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+					return
+				}
+				_, _ = conn.Write([]byte{5, 0})
+				// request: VER CMD RSV ATYP ADDR PORT
+				if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+					return
+				}
+				var host string
+				switch buf[3] {
+				case 1:
+					_, _ = io.ReadFull(conn, buf[:4])
+					host = net.IP(buf[:4]).String()
+				case 3:
+					_, _ = io.ReadFull(conn, buf[:1])
+					n := int(buf[0])
+					_, _ = io.ReadFull(conn, buf[:n])
+					host = string(buf[:n])
+				default:
+					return
+				}
+				_, _ = io.ReadFull(conn, buf[:2])
+				addr := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(buf[:2]))))
+				up, err := net.Dial("tcp", addr)
+				if err != nil {
+					_, _ = conn.Write([]byte{5, 1, 0, 1, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				defer up.Close()
+				mu.Lock()
+				dials++
+				mu.Unlock()
+				_, _ = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+				go func() { _, _ = io.Copy(up, conn) }()
+				_, _ = io.Copy(conn, up)
+			}()
+		}
+	}()
+	return &url.URL{Scheme: "socks5", Host: ln.Addr().String()}, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return dials
+	}
+}
+
+func TestYnisonThroughSocks5(t *testing.T) {
+	proxy, dials := startSocks5(t)
+	srv := fake.NewServer()
+	defer srv.Close()
+	st := auth.New("tok")
+	st.SetDeviceID("dev")
+	c := sdk.New(
+		sdk.WithAuthStorage(st),
+		sdk.WithYnisonURLs(srv.RedirectURL(), srv.StateURL()),
+		sdk.WithRouteProxy(sdk.RouteYnison, proxy),
+	)
+	p, err := c.Ynison.Connect(context.Background())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer p.Close(context.Background())
+	// redirector + state websocket
+	if n := dials(); n != 2 {
+		t.Fatalf("socks5 dials %d", n)
+	}
+}
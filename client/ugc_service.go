@@ -1,13 +1,11 @@
 package client
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"math/rand"
-	"mime/multipart"
 	"net/http"
 	"net/url"
 	"path"
@@ -36,7 +34,7 @@ func (s *UgcService) GetUploadLink(ctx context.Context, pl *playlist.Playlist, f
 	if err != nil {
 		return nil, err
 	}
-	resp, err := s.c.http.Do(req)
+	resp, err := s.c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -56,24 +54,16 @@ func (s *UgcService) UploadBytes(ctx context.Context, upload *ugc.Upload, data [
 	if upload == nil {
 		return nil, fmt.Errorf("upload link nil")
 	}
-	body := &bytes.Buffer{}
-	mw := multipart.NewWriter(body)
-	part, err := mw.CreateFormFile("file", "upload.bin")
+	body, contentType, err := encodeMultipart(multipartFile{Field: "file", FileName: "upload.bin", Data: data})
 	if err != nil {
 		return nil, err
 	}
-	if _, err = part.Write(data); err != nil {
-		return nil, err
-	}
-	_ = mw.Close()
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, upload.PostTarget, body)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", mw.FormDataContentType())
-	if s.c.auth.Token != "" {
-		req.Header.Set("Authorization", "OAuth "+s.c.auth.Token)
-	}
+	req.Header.Set("Content-Type", contentType)
+	s.c.setAuthHeader(req)
 	if s.c.cfg.UserAgent != "" {
 		req.Header.Set("User-Agent", s.c.cfg.UserAgent)
 	}
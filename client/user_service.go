@@ -24,7 +24,7 @@ func (s *UserService) Authorize(ctx context.Context, token string) error {
 	if token == "" {
 		return fmt.Errorf("empty token")
 	}
-	s.c.auth.Token = token
+	s.c.auth.SetToken(token, time.Time{})
 	info, err := s.GetUserAuth(ctx)
 	if err != nil {
 		return err
@@ -35,7 +35,9 @@ func (s *UserService) Authorize(ctx context.Context, token string) error {
 	// persist minimal user info in storage for parity (Uid, Login, IsAuthorized flag)
 	s.c.auth.SetUid(info.Result.Account.Uid)
 	s.c.auth.SetLogin(info.Result.Account.Login)
-	s.c.auth.IsAuthorized = true
+	s.c.auth.SetAuthorized(true)
+	// авторизация уже применена: ошибка сохранения доступна через SaveError
+	s.c.setSaveErr(s.c.saveAuth())
 	return nil
 }
 
@@ -63,7 +65,7 @@ func (s *UserService) CreateAuthSession(ctx context.Context, login string) (*mod
 		return nil, err
 	}
 	body := urlValues(map[string]string{
-		"csrf_token": s.c.auth.AuthToken.CsrfToken,
+		"csrf_token": s.c.auth.Session().CsrfToken,
 		"login":      login,
 	})
 	resp, err := s.passportPOST(ctx, "registration-validations/auth/multi_step/start", body)
@@ -74,10 +76,9 @@ func (s *UserService) CreateAuthSession(ctx context.Context, login string) (*mod
 	if err := decodeJSON(resp, &out); err != nil {
 		return nil, err
 	}
-	if s.c.auth.AuthToken == nil {
-		s.c.auth.AuthToken = &auth.AuthToken{}
-	}
-	s.c.auth.AuthToken.TrackId = out.TrackId
+	sess := s.c.auth.Session()
+	sess.TrackId = out.TrackId
+	s.c.auth.SetSession(sess)
 	return &models.Response[models.AuthTypes]{Result: out}, nil
 }
 
@@ -87,7 +88,7 @@ func (s *UserService) GetAuthQRLink(ctx context.Context) (string, error) {
 		return "", err
 	}
 	body := urlValues(map[string]string{
-		"csrf_token": s.c.auth.AuthToken.CsrfToken,
+		"csrf_token": s.c.auth.Session().CsrfToken,
 		"retpath":    "https://passport.yandex.ru/profile",
 		"with_code":  "1",
 	})
@@ -99,11 +100,7 @@ func (s *UserService) GetAuthQRLink(ctx context.Context) (string, error) {
 	if err := decodeJSON(resp, &qr); err != nil {
 		return "", err
 	}
-	if s.c.auth.AuthToken == nil {
-		s.c.auth.AuthToken = &auth.AuthToken{}
-	}
-	s.c.auth.AuthToken.TrackId = qr.TrackId
-	s.c.auth.AuthToken.CsrfToken = qr.CsrfToken
+	s.c.auth.SetSession(auth.AuthToken{CsrfToken: qr.CsrfToken, TrackId: qr.TrackId})
 	if strings.ToLower(qr.Status) != "ok" {
 		return "", fmt.Errorf("qr status: %s", qr.Status)
 	}
@@ -112,14 +109,20 @@ func (s *UserService) GetAuthQRLink(ctx context.Context) (string, error) {
 
 // AuthorizeByQR опрашивает статус QR до подтверждения или таймаута.
 func (s *UserService) AuthorizeByQR(ctx context.Context, pollInterval time.Duration, maxWait time.Duration) (*models.AuthQRStatus, error) {
-	if s.c.auth.AuthToken == nil || s.c.auth.AuthToken.TrackId == "" {
+	return s.AuthorizeByQRWithStatus(ctx, pollInterval, maxWait, nil)
+}
+
+// AuthorizeByQRWithStatus как AuthorizeByQR, но вызывает onStatus (если задан) после каждого опроса.
+func (s *UserService) AuthorizeByQRWithStatus(ctx context.Context, pollInterval, maxWait time.Duration, onStatus func(*models.AuthQRStatus)) (*models.AuthQRStatus, error) {
+	sess := s.c.auth.Session()
+	if sess.TrackId == "" {
 		return nil, errors.New("qr session not started")
 	}
 	deadline := time.Now().Add(maxWait)
 	for {
 		body := urlValues(map[string]string{
-			"csrf_token": s.c.auth.AuthToken.CsrfToken,
-			"track_id":   s.c.auth.AuthToken.TrackId,
+			"csrf_token": sess.CsrfToken,
+			"track_id":   sess.TrackId,
 		})
 		resp, err := s.passportPOST(ctx, "auth/new/magic/status/", body)
 		if err != nil {
@@ -129,6 +132,9 @@ func (s *UserService) AuthorizeByQR(ctx context.Context, pollInterval time.Durat
 		if err := decodeJSON(resp, &st); err != nil {
 			return nil, err
 		}
+		if onStatus != nil {
+			onStatus(&st)
+		}
 		if strings.ToLower(st.Status) == "ok" && st.MagicLinkConfirmed {
 			if err := s.loginByCookies(ctx); err != nil {
 				return nil, err
@@ -148,12 +154,13 @@ func (s *UserService) AuthorizeByQR(ctx context.Context, pollInterval time.Durat
 
 // GetCaptcha получает captcha.
 func (s *UserService) GetCaptcha(ctx context.Context) (*models.AuthCaptcha, error) {
-	if s.c.auth.AuthToken == nil || s.c.auth.AuthToken.TrackId == "" {
+	sess := s.c.auth.Session()
+	if sess.TrackId == "" {
 		return nil, errors.New("session not started")
 	}
 	body := urlValues(map[string]string{
-		"csrf_token": s.c.auth.AuthToken.CsrfToken,
-		"track_id":   s.c.auth.AuthToken.TrackId,
+		"csrf_token": sess.CsrfToken,
+		"track_id":   sess.TrackId,
 	})
 	resp, err := s.passportPOST(ctx, "registration-validations/textcaptcha", body)
 	if err != nil {
@@ -168,12 +175,13 @@ func (s *UserService) GetCaptcha(ctx context.Context) (*models.AuthCaptcha, erro
 
 // AuthorizeByCaptcha отправляет ответ на captcha.
 func (s *UserService) AuthorizeByCaptcha(ctx context.Context, answer string) (*models.AuthBase, error) {
-	if s.c.auth.AuthToken == nil || s.c.auth.AuthToken.TrackId == "" {
+	sess := s.c.auth.Session()
+	if sess.TrackId == "" {
 		return nil, errors.New("session not started")
 	}
 	body := urlValues(map[string]string{
-		"csrf_token": s.c.auth.AuthToken.CsrfToken,
-		"track_id":   s.c.auth.AuthToken.TrackId,
+		"csrf_token": sess.CsrfToken,
+		"track_id":   sess.TrackId,
 		"answer":     answer,
 	})
 	resp, err := s.passportPOST(ctx, "registration-validations/checkHuman", body)
@@ -189,12 +197,13 @@ func (s *UserService) AuthorizeByCaptcha(ctx context.Context, answer string) (*m
 
 // GetAuthLetter запрашивает отправку письма.
 func (s *UserService) GetAuthLetter(ctx context.Context) (*models.AuthLetter, error) {
-	if s.c.auth.AuthToken == nil || s.c.auth.AuthToken.TrackId == "" {
+	sess := s.c.auth.Session()
+	if sess.TrackId == "" {
 		return nil, errors.New("session not started")
 	}
 	body := urlValues(map[string]string{
-		"csrf_token": s.c.auth.AuthToken.CsrfToken,
-		"track_id":   s.c.auth.AuthToken.TrackId,
+		"csrf_token": sess.CsrfToken,
+		"track_id":   sess.TrackId,
 	})
 	resp, err := s.passportPOST(ctx, "registration-validations/auth/send_magic_letter", body)
 	if err != nil {
@@ -209,12 +218,13 @@ func (s *UserService) GetAuthLetter(ctx context.Context) (*models.AuthLetter, er
 
 // AuthorizeByLetter проверяет статус magic link.
 func (s *UserService) AuthorizeByLetter(ctx context.Context) (*models.AuthLetterStatus, error) {
-	if s.c.auth.AuthToken == nil || s.c.auth.AuthToken.TrackId == "" {
+	sess := s.c.auth.Session()
+	if sess.TrackId == "" {
 		return nil, errors.New("session not started")
 	}
 	body := urlValues(map[string]string{
-		"csrf_token": s.c.auth.AuthToken.CsrfToken,
-		"track_id":   s.c.auth.AuthToken.TrackId,
+		"csrf_token": sess.CsrfToken,
+		"track_id":   sess.TrackId,
 	})
 	resp, err := s.passportPOST(ctx, "auth/letter/status/", body)
 	if err != nil {
@@ -235,12 +245,13 @@ func (s *UserService) AuthorizeByLetter(ctx context.Context) (*models.AuthLetter
 
 // AuthorizeByAppPassword завершает вход приложенческим паролем.
 func (s *UserService) AuthorizeByAppPassword(ctx context.Context, password string) (*models.AuthBase, error) {
-	if s.c.auth.AuthToken == nil || s.c.auth.AuthToken.TrackId == "" {
+	sess := s.c.auth.Session()
+	if sess.TrackId == "" {
 		return nil, errors.New("session not started")
 	}
 	body := urlValues(map[string]string{
-		"csrf_token": s.c.auth.AuthToken.CsrfToken,
-		"track_id":   s.c.auth.AuthToken.TrackId,
+		"csrf_token": sess.CsrfToken,
+		"track_id":   sess.TrackId,
 		"password":   password,
 		"retpath":    "https://passport.yandex.ru/am/finish?status=ok&from=Login",
 	})
@@ -262,14 +273,15 @@ func (s *UserService) AuthorizeByAppPassword(ctx context.Context, password strin
 
 // GetAccessToken обменивает cookies access token на music token (упрощение: предполагаем storage.AccessToken уже установлен внешне).
 func (s *UserService) GetAccessToken(ctx context.Context) (*auth.AccessToken, error) {
-	if s.c.auth.AccessToken == nil || s.c.auth.AccessToken.AccessToken == "" {
+	xtoken := s.c.auth.GetAccessToken()
+	if xtoken == nil || xtoken.AccessToken == "" {
 		return nil, errors.New("access token missing")
 	}
 	form := urlValues(map[string]string{
 		"client_id":     s.c.cfg.ClientID,
 		"client_secret": s.c.cfg.ClientSecret,
 		"grant_type":    "x-token",
-		"access_token":  s.c.auth.AccessToken.AccessToken,
+		"access_token":  xtoken.AccessToken,
 	})
 	resp, err := s.oauthPOST(ctx, "/1/token", form)
 	if err != nil {
@@ -279,10 +291,25 @@ func (s *UserService) GetAccessToken(ctx context.Context) (*auth.AccessToken, er
 	if err := decodeJSON(resp, &acc); err != nil {
 		return nil, err
 	}
-	s.c.auth.Token = acc.AccessToken
+	if acc.AccessToken == "" {
+		return nil, errors.New("empty access token")
+	}
+	acc.IssuedAt = time.Now()
+	s.c.auth.SetToken(acc.AccessToken, acc.ExpiresAt())
+	if s.c.cfg.OnTokenRefreshed != nil {
+		s.c.cfg.OnTokenRefreshed(&acc)
+	}
+	// токен уже действует: ошибка сохранения не должна выглядеть как неудачный обмен
+	s.c.setSaveErr(s.c.saveAuth())
 	return &acc, nil
 }
 
+// AuthorizeByCookies обменивает сессию passport из cookie jar (например, после Client.ImportCookies)
+// на x-token и music token.
+func (s *UserService) AuthorizeByCookies(ctx context.Context) error {
+	return s.loginByCookies(ctx)
+}
+
 // loginByCookies выполняет обмен cookies -> access token (sessionid) затем устанавливает storage.AccessToken.
 func (s *UserService) loginByCookies(ctx context.Context) error {
 	// POST mobile proxy endpoint 1/bundle/oauth/token_by_sessionid
@@ -310,9 +337,11 @@ func (s *UserService) loginByCookies(ctx context.Context) error {
 	if acc.AccessToken == "" {
 		return fmt.Errorf("empty access token")
 	}
-	s.c.auth.AccessToken = &acc
-	s.c.auth.Token = acc.AccessToken
-	s.c.auth.IsAuthorized = true
+	acc.IssuedAt = time.Now()
+	s.c.auth.SetAccessToken(&acc)
+	s.c.auth.SetToken(acc.AccessToken, acc.ExpiresAt())
+	s.c.auth.SetAuthorized(true)
+	s.c.setSaveErr(s.c.saveAuth())
 	return nil
 }
 
@@ -333,7 +362,7 @@ func (s *UserService) GetLoginInfo(ctx context.Context) (*models.LoginInfo, erro
 var reCsrf = regexp.MustCompile(`"csrf_token" value="([^"]+)"`)
 
 func (s *UserService) ensureCsrf(ctx context.Context) error {
-	if s.c.auth.AuthToken != nil && s.c.auth.AuthToken.CsrfToken != "" {
+	if s.c.auth.Session().CsrfToken != "" {
 		return nil
 	}
 	// GET am (auth methods) to extract csrf
@@ -348,7 +377,7 @@ func (s *UserService) ensureCsrf(ctx context.Context) error {
 	if len(m) < 2 {
 		return fmt.Errorf("csrf not found")
 	}
-	s.c.auth.AuthToken = &auth.AuthToken{CsrfToken: string(m[1])}
+	s.c.auth.SetSession(auth.AuthToken{CsrfToken: string(m[1])})
 	return nil
 }
 
@@ -374,7 +403,13 @@ func (s *UserService) passportPOST(ctx context.Context, path string, body string
 	url := "https://passport.yandex.ru/" + path
 	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	return s.c.http.Do(req)
+	resp, err := s.c.http.Do(req)
+	if err == nil {
+		// cookies промежуточных шагов входа сохраняются сразу: прерванный вход
+		// можно продолжить после перезапуска
+		s.c.setSaveErr(s.c.saveAuth())
+	}
+	return resp, err
 }
 
 func (s *UserService) oauthPOST(ctx context.Context, path string, body string) (*http.Response, error) {
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -38,10 +39,16 @@ type Player struct {
 
 // Connect establishes websocket connections and starts read loop.
 func (s *YnisonService) Connect(ctx context.Context) (*Player, error) {
-	if s.c.auth.Token == "" {
+	return s.ConnectWithCallbacks(ctx, nil, nil)
+}
+
+// ConnectWithCallbacks is like Connect but installs OnReceive/OnClose before the read loop
+// starts, so the bootstrap state is not missed.
+func (s *YnisonService) ConnectWithCallbacks(ctx context.Context, onReceive func(p *Player, s *ynison.State), onClose func(p *Player, err error)) (*Player, error) {
+	if s.c.auth.GetToken() == "" {
 		return nil, errors.New("token required")
 	}
-	p := &Player{cli: s.c, st: s.c.auth}
+	p := &Player{cli: s.c, st: s.c.auth, OnReceive: onReceive, OnClose: onClose}
 	if err := p.connect(ctx); err != nil {
 		return nil, err
 	}
@@ -50,8 +57,9 @@ func (s *YnisonService) Connect(ctx context.Context) (*Player, error) {
 
 func (p *Player) connect(ctx context.Context) error {
 	// 1. redirector
-	rc, _, err := websocket.Dial(ctx, "wss://ynison.music.yandex.ru/redirector.YnisonRedirectService/GetRedirectToYnison", &websocket.DialOptions{
+	rc, _, err := websocket.Dial(ctx, p.cli.cfg.YnisonRedirectURL, &websocket.DialOptions{
 		HTTPHeader: p.wsHeaders(""),
+		HTTPClient: p.cli.routeClient(RouteYnison),
 	})
 	if err != nil {
 		return fmt.Errorf("redirect dial: %w", err)
@@ -67,8 +75,11 @@ func (p *Player) connect(ctx context.Context) error {
 		return fmt.Errorf("redirect decode: %w", err)
 	}
 	// 2. state websocket
-	stateURL := fmt.Sprintf("wss://%s/ynison_state.YnisonStateService/PutYnisonState", red.Host)
-	sc, _, err := websocket.Dial(ctx, stateURL, &websocket.DialOptions{HTTPHeader: p.wsHeaders(red.RedirectTicket)})
+	stateURL := ynisonStateURL(p.cli.cfg.YnisonStateURL, red.Host)
+	sc, _, err := websocket.Dial(ctx, stateURL, &websocket.DialOptions{
+		HTTPHeader: p.wsHeaders(red.RedirectTicket),
+		HTTPClient: p.cli.routeClient(RouteYnison),
+	})
 	if err != nil {
 		return fmt.Errorf("state dial: %w", err)
 	}
@@ -93,19 +104,20 @@ func (p *Player) connect(ctx context.Context) error {
 func (p *Player) wsHeaders(ticket string) http.Header {
 	h := http.Header{}
 	h.Set("Origin", "https://music.yandex.ru")
-	h.Set("Authorization", "OAuth "+p.st.Token)
-	protocolMeta := fmt.Sprintf("{\"Ynison-Device-Id\":\"%s\",\"Ynison-Device-Info\":{\"app_name\":\"Chrome\",\"type\":1}}", p.st.DeviceID)
+	cr := p.st.Credentials()
+	h.Set("Authorization", "OAuth "+cr.Token)
+	protocolMeta := fmt.Sprintf("{\"Ynison-Device-Id\":\"%s\",\"Ynison-Device-Info\":{\"app_name\":\"Chrome\",\"type\":1}}", cr.DeviceID)
 	if ticket == "" {
 		h.Set("Sec-WebSocket-Protocol", "Bearer, v2, "+protocolMeta)
 	} else {
-		protocolMetaWithTicket := fmt.Sprintf("{\"Ynison-Device-Id\":\"%s\",\"Ynison-Device-Info\":{\"app_name\":\"Chrome\",\"type\":1},\"Ynison-Redirect-Ticket\":\"%s\"}", p.st.DeviceID, ticket)
+		protocolMetaWithTicket := fmt.Sprintf("{\"Ynison-Device-Id\":\"%s\",\"Ynison-Device-Info\":{\"app_name\":\"Chrome\",\"type\":1},\"Ynison-Redirect-Ticket\":\"%s\"}", cr.DeviceID, ticket)
 		h.Set("Sec-WebSocket-Protocol", "Bearer, v2, "+protocolMetaWithTicket)
 	}
 	return h
 }
 
 func (p *Player) sendDefaultState(ctx context.Context) error {
-	ver := &ynison.Version{DeviceID: p.st.DeviceID, Version: "0", TimestampMs: time.Now().UnixMilli()}
+	ver := &ynison.Version{DeviceID: p.st.GetDeviceID(), Version: "0", TimestampMs: time.Now().UnixMilli()}
 	bootstrap := map[string]any{
 		"update_full_state": map[string]any{
 			"player_state": map[string]any{
@@ -228,3 +240,12 @@ func (p *Player) Close(ctx context.Context) error {
 	}
 	return nil
 }
+
+// ynisonStateURL подставляет host в шаблон Config.YnisonStateURL. Шаблон не считается
+// форматной строкой: литеральные % в URL сохраняются.
+func ynisonStateURL(tmpl, host string) string {
+	if strings.Contains(tmpl, "{host}") {
+		return strings.ReplaceAll(tmpl, "{host}", host)
+	}
+	return strings.Replace(tmpl, "%s", host, 1)
+}
@@ -0,0 +1,157 @@
+package client_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/auth"
+	sdk "github.com/Banjirome/yandex-music-go/client"
+	fake "github.com/Banjirome/yandex-music-go/ymtest/ynison"
+	"github.com/Banjirome/yandex-music-go/ynison"
+)
+
+func newYnisonClient(srv *fake.Server, token, deviceID string) *sdk.Client {
+	st := auth.New(token)
+	st.SetDeviceID(deviceID)
+	return sdk.New(sdk.WithAuthStorage(st), sdk.WithYnisonURLs(srv.RedirectURL(), srv.StateURL()))
+}
+
+// waitState reads states until pred matches or timeout.
+func waitState(t *testing.T, ch <-chan *ynison.State, pred func(*ynison.State) bool) *ynison.State {
+	t.Helper()
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case st := <-ch:
+			if pred(st) {
+				return st
+			}
+		case <-timeout:
+			t.Fatalf("state not received")
+			return nil
+		}
+	}
+}
+
+func stateChan() (chan *ynison.State, func(*sdk.Player, *ynison.State)) {
+	ch := make(chan *ynison.State, 16)
+	return ch, func(_ *sdk.Player, st *ynison.State) { ch <- st }
+}
+
+func TestYnisonConnectAndFanOut(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	ch1, on1 := stateChan()
+	p1, err := newYnisonClient(srv, "t1", "dev-a").Ynison.ConnectWithCallbacks(ctx, on1, nil)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer p1.Close(ctx)
+	waitState(t, ch1, func(st *ynison.State) bool { return len(st.Devices) == 1 })
+
+	ch2, on2 := stateChan()
+	p2, err := newYnisonClient(srv, "t2", "dev-b").Ynison.ConnectWithCallbacks(ctx, on2, nil)
+	if err != nil {
+		t.Fatalf("connect second: %v", err)
+	}
+	defer p2.Close(ctx)
+	// первое устройство получает рассылку о втором
+	waitState(t, ch1, func(st *ynison.State) bool { return len(st.Devices) == 2 })
+	waitState(t, ch2, func(st *ynison.State) bool { return len(st.Devices) == 2 })
+
+	ver := &ynison.Version{DeviceID: "remote", Version: "1", TimestampMs: time.Now().UnixMilli()}
+	ps := &ynison.PlayerState{
+		PlayerQueue: &ynison.PlayerQueue{CurrentPlayableIndex: 0, PlayableList: []ynison.PlayableItem{{PlayableID: "42"}}, Version: ver},
+		Status:      &ynison.PlayerStateStatus{Version: ver},
+	}
+	if err := srv.SetPlayerState(ctx, ps); err != nil {
+		t.Fatalf("set state: %v", err)
+	}
+	for _, ch := range []chan *ynison.State{ch1, ch2} {
+		waitState(t, ch, func(st *ynison.State) bool {
+			return st.PlayerState.PlayerQueue != nil && len(st.PlayerState.PlayerQueue.PlayableList) == 1
+		})
+	}
+	if st := p1.State(); st == nil || st.PlayerState.PlayerQueue.PlayableList[0].PlayableID != "42" {
+		t.Fatalf("cached state not updated: %+v", st)
+	}
+	// shadow bootstrap устройств не должен перетирать состояние
+	for _, u := range srv.Updates() {
+		if u.Kind == "update_full_state" && u.Accepted {
+			t.Fatalf("shadow bootstrap accepted: %+v", u)
+		}
+	}
+}
+
+func TestYnisonErrorClosesPlayer(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+	ch, onReceive := stateChan()
+	closed := make(chan error, 1)
+	p, err := newYnisonClient(srv, "t", "dev-err").Ynison.ConnectWithCallbacks(ctx, onReceive, func(_ *sdk.Player, err error) { closed <- err })
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer p.Close(ctx)
+	waitState(t, ch, func(*ynison.State) bool { return true })
+	if err := srv.SendError(ctx, "dev-err", &ynison.Error{Message: "backoff please"}); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	select {
+	case err := <-closed:
+		if err == nil || !strings.Contains(err.Error(), "backoff please") {
+			t.Fatalf("unexpected close error %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("OnClose not called")
+	}
+}
+
+func TestYnisonKeepAlive(t *testing.T) {
+	srv := fake.NewServer(fake.WithKeepAlive(time.Second, 5*time.Second))
+	defer srv.Close()
+	ctx := context.Background()
+	p, err := newYnisonClient(srv, "t", "dev-ka").Ynison.Connect(ctx)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer p.Close(ctx)
+	deadline := time.Now().Add(3 * time.Second)
+	for srv.Pings() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("no keepalive ping received")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestYnisonRejectsUnknownToken(t *testing.T) {
+	srv := fake.NewServer(fake.WithTokens("good"))
+	defer srv.Close()
+	_, err := newYnisonClient(srv, "bad", "dev-x").Ynison.Connect(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "redirect dial") {
+		t.Fatalf("expected redirect dial error got %v", err)
+	}
+}
+
+func TestYnisonStateURLLiteralPercent(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+	st := auth.New("t1")
+	st.SetDeviceID("dev-a")
+	// %25 в запросе не должен восприниматься как глагол форматирования
+	c := sdk.New(sdk.WithAuthStorage(st), sdk.WithYnisonURLs(srv.RedirectURL(), srv.StateURL()+"?trace=100%25"))
+	ch, on := stateChan()
+	p, err := c.Ynison.ConnectWithCallbacks(ctx, on, nil)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer p.Close(ctx)
+	waitState(t, ch, func(st *ynison.State) bool { return len(st.Devices) == 1 })
+}
@@ -2,4 +2,10 @@ module github.com/Banjirome/yandex-music-go
 
 go 1.22
 
-require nhooyr.io/websocket v1.8.10 // indirect
+require (
+	golang.org/x/crypto v0.33.0
+	nhooyr.io/websocket v1.8.10
+	rsc.io/qr v0.2.0
+)
+
+require golang.org/x/sys v0.30.0 // indirect
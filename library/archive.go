@@ -0,0 +1,274 @@
+package library
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ArchiveVersion текущая версия формата архива библиотеки. Версия 2: исполнители
+// в CSV хранятся JSON-массивом, время — с долями секунды; архивы версии 1 читаются.
+const ArchiveVersion = 2
+
+// csvMagic первая ячейка служебной строки CSV архива: #ymarchive,<version>,<exportedAt>,<uid>.
+const csvMagic = "#ymarchive"
+
+// Archive переносимая копия библиотеки (бэкап и миграция между аккаунтами).
+// Списки лайков идут в порядке библиотеки (новые первыми).
+type Archive struct {
+	Version        int               `json:"version"`
+	ExportedAt     time.Time         `json:"exportedAt"`
+	UID            string            `json:"uid"`
+	LikedTracks    []ArchiveTrack    `json:"likedTracks"`
+	DislikedTracks []ArchiveTrack    `json:"dislikedTracks"`
+	LikedAlbums    []ArchiveEntity   `json:"likedAlbums"`
+	LikedArtists   []ArchiveEntity   `json:"likedArtists"`
+	LikedPlaylists []ArchiveEntity   `json:"likedPlaylists"` // ID — "uid:kind"
+	Playlists      []ArchivePlaylist `json:"playlists"`
+}
+
+// ArchiveTrack трек архива с метаданными для сопоставления человеком или другим сервисом.
+type ArchiveTrack struct {
+	ID         string    `json:"id"`
+	AlbumID    string    `json:"albumId,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	Artists    []string  `json:"artists,omitempty"`
+	Album      string    `json:"album,omitempty"`
+	DurationMs int64     `json:"durationMs,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Key ключ трека "id:albumId" (или id без альбома).
+func (t ArchiveTrack) Key() string {
+	if t.AlbumID == "" {
+		return t.ID
+	}
+	return t.ID + ":" + t.AlbumID
+}
+
+// ArchiveEntity лайкнутый альбом, исполнитель или плейлист.
+type ArchiveEntity struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ArchivePlaylist собственный плейлист пользователя.
+type ArchivePlaylist struct {
+	Kind        string         `json:"kind"`
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	Tracks      []ArchiveTrack `json:"tracks"`
+}
+
+// типы строк CSV архива
+const (
+	recLikedTrack    = "liked_track"
+	recDislikedTrack = "disliked_track"
+	recLikedAlbum    = "liked_album"
+	recLikedArtist   = "liked_artist"
+	recLikedPlaylist = "liked_playlist"
+	recPlaylist      = "playlist"
+	recPlaylistTrack = "playlist_track"
+)
+
+var csvHeader = []string{"record", "id", "album_id", "title", "artists", "album", "duration_ms", "timestamp", "playlist", "description"}
+
+// legacyArtistSep разделитель имён исполнителей в CSV версии 1 (может встречаться
+// в самих именах, поэтому с версии 2 ячейка — JSON-массив).
+const legacyArtistSep = "; "
+
+// WriteJSON пишет архив в JSON.
+func (a *Archive) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a)
+}
+
+// WriteCSV пишет архив одной CSV таблицей: служебная строка с версией, заголовок,
+// затем строка на каждый элемент (record — тип строки).
+func (a *Archive) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{csvMagic, strconv.Itoa(a.Version), formatTime(a.ExportedAt), a.UID})
+	_ = cw.Write(csvHeader)
+	track := func(rec, playlist string, t ArchiveTrack) {
+		dur := ""
+		if t.DurationMs > 0 {
+			dur = strconv.FormatInt(t.DurationMs, 10)
+		}
+		artists := ""
+		if len(t.Artists) > 0 {
+			data, _ := json.Marshal(t.Artists) // []string кодируется всегда
+			artists = string(data)
+		}
+		_ = cw.Write([]string{rec, t.ID, t.AlbumID, t.Title, artists, t.Album, dur, formatTime(t.Timestamp), playlist, ""})
+	}
+	entity := func(rec string, e ArchiveEntity) {
+		_ = cw.Write([]string{rec, e.ID, "", e.Title, "", "", "", formatTime(e.Timestamp), "", ""})
+	}
+	for _, t := range a.LikedTracks {
+		track(recLikedTrack, "", t)
+	}
+	for _, t := range a.DislikedTracks {
+		track(recDislikedTrack, "", t)
+	}
+	for _, e := range a.LikedAlbums {
+		entity(recLikedAlbum, e)
+	}
+	for _, e := range a.LikedArtists {
+		entity(recLikedArtist, e)
+	}
+	for _, e := range a.LikedPlaylists {
+		entity(recLikedPlaylist, e)
+	}
+	for _, pl := range a.Playlists {
+		_ = cw.Write([]string{recPlaylist, pl.Kind, "", pl.Title, "", "", "", "", pl.Kind, pl.Description})
+		for _, t := range pl.Tracks {
+			track(recPlaylistTrack, pl.Kind, t)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadArchive читает архив в JSON или CSV (формат определяется по первому символу)
+// и проверяет версию.
+func ReadArchive(r io.Reader) (*Archive, error) {
+	br := bufio.NewReader(r)
+	var first byte
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("library archive: %w", err)
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			first = b
+			_ = br.UnreadByte()
+			break
+		}
+	}
+	var a *Archive
+	var err error
+	if first == '{' {
+		a = &Archive{}
+		if err = json.NewDecoder(br).Decode(a); err != nil {
+			return nil, fmt.Errorf("library archive: decode: %w", err)
+		}
+	} else if a, err = readCSV(br); err != nil {
+		return nil, err
+	}
+	if a.Version < 1 || a.Version > ArchiveVersion {
+		return nil, fmt.Errorf("library archive: unsupported version %d", a.Version)
+	}
+	return a, nil
+}
+
+func readCSV(r io.Reader) (*Archive, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	meta, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("library archive: %w", err)
+	}
+	if len(meta) < 4 || meta[0] != csvMagic {
+		return nil, fmt.Errorf("library archive: not an archive (missing %s line)", csvMagic)
+	}
+	a := &Archive{UID: meta[3]}
+	if a.Version, err = strconv.Atoi(meta[1]); err != nil {
+		return nil, fmt.Errorf("library archive: bad version %q", meta[1])
+	}
+	if a.ExportedAt, err = parseTime(meta[2]); err != nil {
+		return nil, err
+	}
+	if _, err := cr.Read(); err != nil { // заголовок
+		return nil, fmt.Errorf("library archive: %w", err)
+	}
+	byKind := map[string]int{}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("library archive: %w", err)
+		}
+		if len(rec) < len(csvHeader) {
+			line, _ := cr.FieldPos(0)
+			return nil, fmt.Errorf("library archive: line %d: %d fields, want %d", line, len(rec), len(csvHeader))
+		}
+		ts, err := parseTime(rec[7])
+		if err != nil {
+			return nil, err
+		}
+		switch rec[0] {
+		case recLikedTrack, recDislikedTrack, recPlaylistTrack:
+			t := ArchiveTrack{ID: rec[1], AlbumID: rec[2], Title: rec[3], Album: rec[5], Timestamp: ts}
+			switch {
+			case rec[4] == "":
+			case a.Version < 2:
+				t.Artists = strings.Split(rec[4], legacyArtistSep)
+			default:
+				if err := json.Unmarshal([]byte(rec[4]), &t.Artists); err != nil {
+					return nil, fmt.Errorf("library archive: bad artists %q", rec[4])
+				}
+			}
+			if rec[6] != "" {
+				if t.DurationMs, err = strconv.ParseInt(rec[6], 10, 64); err != nil {
+					return nil, fmt.Errorf("library archive: bad duration %q", rec[6])
+				}
+			}
+			switch rec[0] {
+			case recLikedTrack:
+				a.LikedTracks = append(a.LikedTracks, t)
+			case recDislikedTrack:
+				a.DislikedTracks = append(a.DislikedTracks, t)
+			default:
+				i, ok := byKind[rec[8]]
+				if !ok {
+					return nil, fmt.Errorf("library archive: track %s references unknown playlist %q", t.ID, rec[8])
+				}
+				a.Playlists[i].Tracks = append(a.Playlists[i].Tracks, t)
+			}
+		case recLikedAlbum, recLikedArtist, recLikedPlaylist:
+			e := ArchiveEntity{ID: rec[1], Title: rec[3], Timestamp: ts}
+			switch rec[0] {
+			case recLikedAlbum:
+				a.LikedAlbums = append(a.LikedAlbums, e)
+			case recLikedArtist:
+				a.LikedArtists = append(a.LikedArtists, e)
+			default:
+				a.LikedPlaylists = append(a.LikedPlaylists, e)
+			}
+		case recPlaylist:
+			byKind[rec[1]] = len(a.Playlists)
+			a.Playlists = append(a.Playlists, ArchivePlaylist{Kind: rec[1], Title: rec[3], Description: rec[9]})
+		default:
+			return nil, fmt.Errorf("library archive: unknown record %q", rec[0])
+		}
+	}
+	return a, nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	// доли секунды сохраняют порядок лайков, сделанных в одну секунду
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("library archive: bad timestamp %q", s)
+	}
+	return t, nil
+}
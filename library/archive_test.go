@@ -0,0 +1,74 @@
+package library_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/library"
+)
+
+func sampleArchive() *library.Archive {
+	ts := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	// лайк в ту же секунду: порядок держится на долях секунды
+	ts2 := ts.Add(250 * time.Millisecond)
+	return &library.Archive{
+		Version:    library.ArchiveVersion,
+		ExportedAt: ts,
+		UID:        "7",
+		LikedTracks: []library.ArchiveTrack{{ID: "1", AlbumID: "10", Title: "Группа крови", Artists: []string{"Кино", "Цой, В."}, Album: "Группа крови", DurationMs: 287000, Timestamp: ts2},
+			{ID: "4", Title: "Duet", Artists: []string{"Earth; Wind & Fire", "[x]"}, Timestamp: ts}},
+		DislikedTracks: []library.ArchiveTrack{{ID: "2", Timestamp: ts}},
+		LikedAlbums:    []library.ArchiveEntity{{ID: "10", Title: "Группа крови", Timestamp: ts}},
+		LikedArtists:   []library.ArchiveEntity{{ID: "5", Title: "Кино", Timestamp: ts}},
+		LikedPlaylists: []library.ArchiveEntity{{ID: "9:3", Title: "Road", Timestamp: ts}},
+		Playlists: []library.ArchivePlaylist{
+			{Kind: "1000", Title: "Мой \"микс\"", Description: "line1\nline2", Tracks: []library.ArchiveTrack{{ID: "1", AlbumID: "10", Title: "t"}, {ID: "3"}}},
+			{Kind: "1001", Title: "Пустой"},
+		},
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	for name, write := range map[string]func(*library.Archive, *bytes.Buffer) error{
+		"json": func(a *library.Archive, b *bytes.Buffer) error { return a.WriteJSON(b) },
+		"csv":  func(a *library.Archive, b *bytes.Buffer) error { return a.WriteCSV(b) },
+	} {
+		in := sampleArchive()
+		var buf bytes.Buffer
+		if err := write(in, &buf); err != nil {
+			t.Fatalf("%s write: %v", name, err)
+		}
+		out, err := library.ReadArchive(&buf)
+		if err != nil {
+			t.Fatalf("%s read: %v", name, err)
+		}
+		if !reflect.DeepEqual(in, out) {
+			t.Fatalf("%s round trip mismatch:\n in %+v\nout %+v", name, in, out)
+		}
+	}
+}
+
+func TestArchiveReadsVersion1CSV(t *testing.T) {
+	src := "#ymarchive,1,2024-04-01T12:00:00Z,7\n" +
+		"record,id,album_id,title,artists,album,duration_ms,timestamp,playlist,description\n" +
+		"liked_track,1,10,t,A; B,,,2024-04-01T12:00:00Z,,\n"
+	a, err := library.ReadArchive(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := a.LikedTracks[0].Artists; len(got) != 2 || got[1] != "B" {
+		t.Fatalf("artists %q", got)
+	}
+}
+
+func TestArchiveVersionCheck(t *testing.T) {
+	if _, err := library.ReadArchive(strings.NewReader(`{"version":99}`)); err == nil {
+		t.Fatalf("expected unsupported version error")
+	}
+	if _, err := library.ReadArchive(strings.NewReader("record,id\nliked_track,1\n")); err == nil {
+		t.Fatalf("expected error for csv without version line")
+	}
+}
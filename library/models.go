@@ -1,6 +1,11 @@
 package library
 
-import "time"
+import (
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/artist"
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
 
 // Section enumerations
 // (These map to path segments and are lowercased)
@@ -40,9 +45,15 @@ type LibraryAlbum struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// LibraryArtist исполнитель из лайков/дизлайков со временем добавления (with-timestamps=true).
+type LibraryArtist struct {
+	Artist    artist.Artist `json:"artist"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
 type LibraryPlaylists struct {
-	Playlist  any       `json:"playlist"` // placeholder until playlist model ported fully
-	Timestamp time.Time `json:"timestamp"`
+	Playlist  playlist.Playlist `json:"playlist"`
+	Timestamp time.Time         `json:"timestamp"`
 }
 
 // Recently listened context models
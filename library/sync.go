@@ -0,0 +1,137 @@
+package library
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Item элемент раздела библиотеки в снимке синхронизации.
+type Item struct {
+	ID        string    `json:"id"`
+	AlbumID   string    `json:"albumId,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+}
+
+// Snapshot локальное состояние лайков для инкрементальной синхронизации.
+type Snapshot struct {
+	UID string `json:"uid"`
+	// Revision ревизия лайкнутых треков на момент снимка.
+	Revision int                `json:"revision"`
+	Items    map[Section][]Item `json:"items"`
+	SyncedAt time.Time          `json:"syncedAt"`
+}
+
+// EventKind тип изменения.
+type EventKind string
+
+const (
+	EventAdded   EventKind = "added"
+	EventRemoved EventKind = "removed"
+)
+
+// Event изменение раздела библиотеки между двумя снимками.
+type Event struct {
+	Kind    EventKind
+	Section Section
+	Item    Item
+}
+
+// Diff сравнивает два списка элементов раздела: добавленные в порядке cur,
+// удалённые в порядке prev. Элементы сравниваются по ID.
+func Diff(section Section, prev, cur []Item) []Event {
+	was := make(map[string]struct{}, len(prev))
+	for _, it := range prev {
+		was[it.ID] = struct{}{}
+	}
+	now := make(map[string]struct{}, len(cur))
+	var out []Event
+	for _, it := range cur {
+		now[it.ID] = struct{}{}
+		if _, ok := was[it.ID]; !ok {
+			out = append(out, Event{Kind: EventAdded, Section: section, Item: it})
+		}
+	}
+	for _, it := range prev {
+		if _, ok := now[it.ID]; !ok {
+			out = append(out, Event{Kind: EventRemoved, Section: section, Item: it})
+		}
+	}
+	return out
+}
+
+// SyncStore хранилище снимка синхронизации.
+// Load возвращает (nil, nil), если снимка ещё нет.
+type SyncStore interface {
+	Load() (*Snapshot, error)
+	Save(snap *Snapshot) error
+}
+
+// MemoryStore SyncStore в памяти процесса.
+type MemoryStore struct {
+	mu   sync.Mutex
+	snap *Snapshot
+}
+
+// NewMemoryStore создаёт пустое хранилище в памяти.
+func NewMemoryStore() *MemoryStore { return &MemoryStore{} }
+
+func (m *MemoryStore) Load() (*Snapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snap, nil
+}
+
+func (m *MemoryStore) Save(snap *Snapshot) error {
+	m.mu.Lock()
+	m.snap = snap
+	m.mu.Unlock()
+	return nil
+}
+
+// FileStore хранит снимок в JSON файле.
+type FileStore struct{ path string }
+
+// NewFileStore создаёт файловое хранилище снимка.
+func NewFileStore(path string) *FileStore { return &FileStore{path: path} }
+
+// Load читает снимок из файла.
+func (f *FileStore) Load() (*Snapshot, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("library sync store: decode: %w", err)
+	}
+	return &snap, nil
+}
+
+// Save атомарно записывает снимок (временный файл + rename).
+func (f *FileStore) Save(snap *Snapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), ".library-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
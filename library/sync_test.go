@@ -0,0 +1,46 @@
+package library_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Banjirome/yandex-music-go/library"
+)
+
+func TestDiffOrder(t *testing.T) {
+	prev := []library.Item{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	cur := []library.Item{{ID: "d"}, {ID: "b"}, {ID: "e"}}
+	got := library.Diff(library.SectionAlbums, prev, cur)
+	want := []string{"added d", "added e", "removed a", "removed c"}
+	if len(got) != len(want) {
+		t.Fatalf("diff %+v", got)
+	}
+	for i, ev := range got {
+		if s := string(ev.Kind) + " " + ev.Item.ID; s != want[i] || ev.Section != library.SectionAlbums {
+			t.Fatalf("event %d = %q, want %q", i, s, want[i])
+		}
+	}
+}
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	fs := library.NewFileStore(filepath.Join(t.TempDir(), "library.json"))
+	if snap, err := fs.Load(); snap != nil || err != nil {
+		t.Fatalf("empty store: %v %v", snap, err)
+	}
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	in := &library.Snapshot{UID: "7", Revision: 3, Items: map[library.Section][]library.Item{
+		library.SectionTracks: {{ID: "1", AlbumID: "10", Timestamp: ts}},
+	}}
+	if err := fs.Save(in); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	out, err := fs.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	tr := out.Items[library.SectionTracks]
+	if out.Revision != 3 || len(tr) != 1 || tr[0].AlbumID != "10" || !tr[0].Timestamp.Equal(ts) {
+		t.Fatalf("round trip %+v", out)
+	}
+}
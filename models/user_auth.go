@@ -38,8 +38,9 @@ type AuthLetterStatus struct {
 }
 
 type AuthBase struct {
-	Status  string `json:"status"`
-	TrackId string `json:"track_id"`
+	Status  string   `json:"status"`
+	TrackId string   `json:"track_id"`
+	Errors  []string `json:"errors,omitempty"`
 }
 
 type LoginInfo struct {
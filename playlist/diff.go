@@ -0,0 +1,151 @@
+package playlist
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Операции ChangeRequest.
+const (
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// String ключ "id:albumId" (или id без альбома).
+func (k TrackKey) String() string {
+	if k.AlbumId == "" {
+		return k.Id
+	}
+	return k.Id + ":" + k.AlbumId
+}
+
+// Key ключ трека контейнера (альбом — первый из Track.Albums, иначе AlbumID контейнера).
+func (tc TrackContainer) Key() TrackKey {
+	if tc.Track == nil {
+		return TrackKey{Id: tc.ID, AlbumId: tc.AlbumID}
+	}
+	k := TrackKey{Id: tc.Track.ID, AlbumId: tc.AlbumID}
+	if len(tc.Track.Albums) > 0 {
+		k.AlbumId = tc.Track.Albums[0].ID
+	}
+	return k
+}
+
+// Keys ключи треков плейлиста в порядке плейлиста.
+func (p *Playlist) Keys() []TrackKey {
+	out := make([]TrackKey, 0, len(p.Tracks))
+	for _, tc := range p.Tracks {
+		if k := tc.Key(); k.Id != "" {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// MarshalJSON пишет только поля, относящиеся к операции: нулевые at/from
+// значимы и не должны теряться из-за omitempty.
+func (c ChangeRequest) MarshalJSON() ([]byte, error) {
+	switch c.Operation {
+	case OpInsert:
+		return json.Marshal(struct {
+			Operation string     `json:"operation"`
+			At        int        `json:"at"`
+			Tracks    []TrackKey `json:"tracks"`
+		}{c.Operation, c.At, c.Tracks})
+	case OpDelete:
+		return json.Marshal(struct {
+			Operation string     `json:"operation"`
+			From      int        `json:"from"`
+			To        int        `json:"to"`
+			Tracks    []TrackKey `json:"tracks,omitempty"`
+		}{c.Operation, c.From, c.To, c.Tracks})
+	}
+	type plain ChangeRequest
+	return json.Marshal(plain(c))
+}
+
+// Diff строит минимальную последовательность операций, превращающую current в desired.
+// Треки сравниваются по Id. Операции применяются сервером последовательно, индексы
+// каждой рассчитаны на состояние после предыдущих: сначала удаления снизу вверх,
+// затем вставки сверху вниз. Перемещение трека выражается парой delete+insert
+// (отдельной операции перемещения в API нет).
+func Diff(current, desired []TrackKey) []ChangeRequest {
+	keep := lcs(current, desired)
+	var out []ChangeRequest
+
+	// удаления: непрерывные диапазоны вне LCS, с конца, чтобы не сдвигать индексы
+	inCur := make([]bool, len(current))
+	for _, m := range keep {
+		inCur[m[0]] = true
+	}
+	for i := len(current) - 1; i >= 0; {
+		if inCur[i] {
+			i--
+			continue
+		}
+		to := i + 1
+		for i >= 0 && !inCur[i] {
+			i--
+		}
+		from := i + 1
+		out = append(out, ChangeRequest{Operation: OpDelete, From: from, To: to, Tracks: append([]TrackKey(nil), current[from:to]...)})
+	}
+
+	// после удалений остаётся LCS; вставляем недостающее на итоговые позиции по возрастанию
+	inDes := make([]bool, len(desired))
+	for _, m := range keep {
+		inDes[m[1]] = true
+	}
+	for j := 0; j < len(desired); {
+		if inDes[j] {
+			j++
+			continue
+		}
+		at := j
+		for j < len(desired) && !inDes[j] {
+			j++
+		}
+		out = append(out, ChangeRequest{Operation: OpInsert, At: at, Tracks: append([]TrackKey(nil), desired[at:j]...)})
+	}
+	return out
+}
+
+// lcs наибольшая общая подпоследовательность по Id (Hunt–Szymanski: LIS по позициям
+// совпадений, O(r log n)). Возвращает пары индексов (current, desired) по возрастанию.
+func lcs(a, b []TrackKey) [][2]int {
+	pos := map[string][]int{}
+	for i, k := range a {
+		pos[k.Id] = append(pos[k.Id], i)
+	}
+	type node struct {
+		i, j int
+		prev *node
+	}
+	// tails[l] — узел с минимальным i, завершающий общую подпоследовательность длины l+1
+	var tails []*node
+	for j, k := range b {
+		ps := pos[k.Id]
+		// позиции по убыванию: один элемент b не может войти в цепочку дважды
+		for x := len(ps) - 1; x >= 0; x-- {
+			i := ps[x]
+			l := sort.Search(len(tails), func(n int) bool { return tails[n].i >= i })
+			n := &node{i: i, j: j}
+			if l > 0 {
+				n.prev = tails[l-1]
+			}
+			if l == len(tails) {
+				tails = append(tails, n)
+			} else {
+				tails[l] = n
+			}
+		}
+	}
+	if len(tails) == 0 {
+		return nil
+	}
+	out := make([][2]int, len(tails))
+	for n, x := tails[len(tails)-1], len(tails)-1; n != nil; n, x = n.prev, x-1 {
+		out[x] = [2]int{n.i, n.j}
+	}
+	return out
+}
@@ -0,0 +1,95 @@
+package playlist_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
+
+// apply применяет операции так же, как сервер: последовательно.
+func apply(t *testing.T, cur []playlist.TrackKey, ops []playlist.ChangeRequest) []playlist.TrackKey {
+	out := append([]playlist.TrackKey(nil), cur...)
+	for _, op := range ops {
+		switch op.Operation {
+		case playlist.OpDelete:
+			if op.From < 0 || op.To > len(out) || op.From >= op.To {
+				t.Fatalf("bad delete range %d..%d of %d", op.From, op.To, len(out))
+			}
+			for i, k := range op.Tracks {
+				if out[op.From+i].Id != k.Id {
+					t.Fatalf("delete tracks mismatch at %d: %v vs %v", op.From+i, out[op.From+i], k)
+				}
+			}
+			out = append(out[:op.From], out[op.To:]...)
+		case playlist.OpInsert:
+			if op.At < 0 || op.At > len(out) {
+				t.Fatalf("bad insert position %d of %d", op.At, len(out))
+			}
+			out = append(out[:op.At], append(append([]playlist.TrackKey(nil), op.Tracks...), out[op.At:]...)...)
+		}
+	}
+	return out
+}
+
+func keys(s string) []playlist.TrackKey {
+	var out []playlist.TrackKey
+	for _, id := range strings.Fields(s) {
+		out = append(out, playlist.TrackKey{Id: id})
+	}
+	return out
+}
+
+func ids(ks []playlist.TrackKey) string {
+	parts := make([]string, len(ks))
+	for i, k := range ks {
+		parts[i] = k.Id
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestDiffMinimal(t *testing.T) {
+	cur, want := keys("a b c d e"), keys("a x c d y e")
+	ops := playlist.Diff(cur, want)
+	// удалить b, вставить x и y
+	if len(ops) != 3 {
+		t.Fatalf("expected 3 ops, got %+v", ops)
+	}
+	if got := ids(apply(t, cur, ops)); got != ids(want) {
+		t.Fatalf("got %q want %q", got, ids(want))
+	}
+	if len(playlist.Diff(cur, cur)) != 0 {
+		t.Fatalf("identical lists must give no ops")
+	}
+}
+
+func TestDiffRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	gen := func() []playlist.TrackKey {
+		n := rnd.Intn(12)
+		out := make([]playlist.TrackKey, n)
+		for i := range out {
+			out[i] = playlist.TrackKey{Id: strconv.Itoa(rnd.Intn(8))} // допускаем повторы
+		}
+		return out
+	}
+	for i := 0; i < 2000; i++ {
+		cur, want := gen(), gen()
+		if got := apply(t, cur, playlist.Diff(cur, want)); ids(got) != ids(want) {
+			t.Fatalf("case %d: %q -> %q, got %q", i, ids(cur), ids(want), ids(got))
+		}
+	}
+}
+
+func TestChangeRequestZeroIndexes(t *testing.T) {
+	data, _ := json.Marshal([]playlist.ChangeRequest{
+		{Operation: playlist.OpInsert, At: 0, Tracks: keys("a")},
+		{Operation: playlist.OpDelete, From: 0, To: 1},
+	})
+	if s := string(data); !strings.Contains(s, `"at":0`) || !strings.Contains(s, `"from":0`) || strings.Contains(s, `"to":0`) {
+		t.Fatalf("unexpected json %s", s)
+	}
+}
@@ -0,0 +1,76 @@
+package playlist
+
+// Edit намерение изменить плейлист, не привязанное к ревизии: при конфликте его
+// можно заново применить к свежему содержимому (см. PlaylistService.Edit).
+// Шаги выполняются по порядку: Remove, Insert, Move.
+type Edit struct {
+	// Remove удаляет все вхождения треков (по Id).
+	Remove []TrackKey
+	// Insert вставляет треки; уже присутствующие пропускаются, если не AllowDuplicates,
+	// поэтому повтор того же Edit не создаёт дублей.
+	Insert []Insert
+	// Move перемещает первое вхождение трека на позицию To (отсутствующие игнорируются).
+	Move []Move
+	// AllowDuplicates разрешает вставлять треки, которые уже есть в плейлисте.
+	AllowDuplicates bool
+}
+
+// Insert вставка Tracks перед позицией At; At < 0 или за концом — в конец.
+type Insert struct {
+	At     int
+	Tracks []TrackKey
+}
+
+// Move перемещение трека на итоговую позицию To (ограничивается длиной плейлиста).
+type Move struct {
+	Track TrackKey
+	To    int
+}
+
+// Apply возвращает список треков после применения намерения к current.
+func (e Edit) Apply(current []TrackKey) []TrackKey {
+	out := make([]TrackKey, 0, len(current))
+	drop := make(map[string]bool, len(e.Remove))
+	for _, k := range e.Remove {
+		drop[k.Id] = true
+	}
+	present := make(map[string]bool, len(current))
+	for _, k := range current {
+		if !drop[k.Id] {
+			out = append(out, k)
+			present[k.Id] = true
+		}
+	}
+	for _, ins := range e.Insert {
+		var add []TrackKey
+		for _, k := range ins.Tracks {
+			if !e.AllowDuplicates && present[k.Id] {
+				continue
+			}
+			present[k.Id] = true
+			add = append(add, k)
+		}
+		at := ins.At
+		if at < 0 || at > len(out) {
+			at = len(out)
+		}
+		out = append(out[:at], append(add, out[at:]...)...)
+	}
+	for _, mv := range e.Move {
+		from := -1
+		for i, k := range out {
+			if k.Id == mv.Track.Id {
+				from = i
+				break
+			}
+		}
+		if from < 0 {
+			continue
+		}
+		k := out[from]
+		out = append(out[:from], out[from+1:]...)
+		to := min(max(mv.To, 0), len(out))
+		out = append(out[:to], append([]TrackKey{k}, out[to:]...)...)
+	}
+	return out
+}
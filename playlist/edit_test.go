@@ -0,0 +1,28 @@
+package playlist_test
+
+import (
+	"testing"
+
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
+
+func TestEditApply(t *testing.T) {
+	e := playlist.Edit{
+		Remove: keys("b"),
+		Insert: []playlist.Insert{{At: 0, Tracks: keys("x a")}, {At: -1, Tracks: keys("y")}},
+		Move:   []playlist.Move{{Track: playlist.TrackKey{Id: "d"}, To: 0}, {Track: playlist.TrackKey{Id: "zz"}, To: 1}},
+	}
+	// a уже есть — не дублируется; zz отсутствует — перемещение игнорируется
+	if got := ids(e.Apply(keys("a b c d"))); got != "d x a c y" {
+		t.Fatalf("got %q", got)
+	}
+	// повторное применение к результату ничего не меняет, кроме перемещения
+	again := e.Apply(e.Apply(keys("a b c d")))
+	if got := ids(again); got != "d x a c y" {
+		t.Fatalf("not idempotent: %q", got)
+	}
+	dup := playlist.Edit{Insert: []playlist.Insert{{At: 99, Tracks: keys("a")}}, AllowDuplicates: true}
+	if got := ids(dup.Apply(keys("a"))); got != "a a" {
+		t.Fatalf("duplicates: %q", got)
+	}
+}
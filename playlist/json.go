@@ -0,0 +1,110 @@
+package playlist
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// flexID id, который API отдаёт то строкой, то числом.
+type flexID string
+
+func (f *flexID) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*f = flexID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("playlist: id must be string or number: %s", b)
+	}
+	*f = flexID(n)
+	return nil
+}
+
+func (p *Playlist) UnmarshalJSON(data []byte) error {
+	type plain Playlist
+	aux := struct {
+		*plain
+		Kind flexID `json:"kind"`
+		Uid  flexID `json:"uid"`
+	}{plain: (*plain)(p), Kind: flexID(p.Kind), Uid: flexID(p.Uid)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	p.Kind, p.Uid = string(aux.Kind), string(aux.Uid)
+	return nil
+}
+
+func (o *Owner) UnmarshalJSON(data []byte) error {
+	type plain Owner
+	aux := struct {
+		*plain
+		Uid flexID `json:"uid"`
+	}{plain: (*plain)(o), Uid: flexID(o.Uid)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	o.Uid = string(aux.Uid)
+	return nil
+}
+
+func (tc *TrackContainer) UnmarshalJSON(data []byte) error {
+	type plain TrackContainer
+	aux := struct {
+		*plain
+		ID      flexID `json:"id"`
+		AlbumID flexID `json:"albumId"`
+	}{plain: (*plain)(tc), ID: flexID(tc.ID), AlbumID: flexID(tc.AlbumID)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	tc.ID, tc.AlbumID = string(aux.ID), string(aux.AlbumID)
+	return nil
+}
+
+func (t *Track) UnmarshalJSON(data []byte) error {
+	type plain Track
+	aux := struct {
+		*plain
+		ID     flexID `json:"id"`
+		RealID flexID `json:"realId"`
+	}{plain: (*plain)(t), ID: flexID(t.ID), RealID: flexID(t.RealID)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	t.ID, t.RealID = string(aux.ID), string(aux.RealID)
+	return nil
+}
+
+func (a *TrackArtist) UnmarshalJSON(data []byte) error {
+	type plain TrackArtist
+	aux := struct {
+		*plain
+		ID flexID `json:"id"`
+	}{plain: (*plain)(a), ID: flexID(a.ID)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	a.ID = string(aux.ID)
+	return nil
+}
+
+func (a *TrackAlbum) UnmarshalJSON(data []byte) error {
+	type plain TrackAlbum
+	aux := struct {
+		*plain
+		ID flexID `json:"id"`
+	}{plain: (*plain)(a), ID: flexID(a.ID)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	a.ID = string(aux.ID)
+	return nil
+}
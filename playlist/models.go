@@ -2,7 +2,7 @@ package playlist
 
 import "time"
 
-// Playlist минимизированная типизация из оригинальной модели YPlaylist.
+// Playlist модель YPlaylist. Числовые kind/uid из API декодируются в строки.
 type Playlist struct {
 	Kind                  string           `json:"kind"`
 	Title                 string           `json:"title"`
@@ -17,6 +17,59 @@ type Playlist struct {
 	LikesCount            int              `json:"likesCount,omitempty"`
 	Created               *time.Time       `json:"created,omitempty"`
 	GeneratedPlaylistType string           `json:"generatedPlaylistType,omitempty"`
+
+	PlaylistUUID         string       `json:"playlistUuid,omitempty"`
+	Visibility           Visibility   `json:"visibility,omitempty"`
+	Available            bool         `json:"available,omitempty"`
+	Collective           bool         `json:"collective,omitempty"`
+	Modified             *time.Time   `json:"modified,omitempty"`
+	DescriptionFormatted string       `json:"descriptionFormatted,omitempty"`
+	Cover                *Cover       `json:"cover,omitempty"`
+	CoverWithoutText     *Cover       `json:"coverWithoutText,omitempty"`
+	OgImage              string       `json:"ogImage,omitempty"`
+	OgTitle              string       `json:"ogTitle,omitempty"`
+	BackgroundColor      string       `json:"backgroundColor,omitempty"`
+	BackgroundImageURL   string       `json:"backgroundImageUrl,omitempty"`
+	TextColor            string       `json:"textColor,omitempty"`
+	Tags                 []Tag        `json:"tags,omitempty"`
+	MadeFor              *MadeFor     `json:"madeFor,omitempty"`
+	PlayCounter          *PlayCounter `json:"playCounter,omitempty"`
+	SimilarPlaylists     []Playlist   `json:"similarPlaylists,omitempty"`
+	LastOwnerPlaylists   []Playlist   `json:"lastOwnerPlaylists,omitempty"`
+	IsBanner             bool         `json:"isBanner,omitempty"`
+	IsPremiere           bool         `json:"isPremiere,omitempty"`
+	EverPlayed           bool         `json:"everPlayed,omitempty"`
+	URLPart              string       `json:"urlPart,omitempty"`
+	IDForFrom            string       `json:"idForFrom,omitempty"`
+}
+
+// Tag тег плейлиста.
+type Tag struct {
+	ID    string `json:"id"`
+	Value string `json:"value"`
+}
+
+// MadeFor для кого собран персональный плейлист.
+type MadeFor struct {
+	UserInfo  *Owner     `json:"userInfo,omitempty"`
+	CaseForms *CaseForms `json:"caseForms,omitempty"`
+}
+
+// CaseForms имя пользователя в падежах (для заголовков «Плейлист дня для ...»).
+type CaseForms struct {
+	Nominative    string `json:"nominative,omitempty"`
+	Genitive      string `json:"genitive,omitempty"`
+	Dative        string `json:"dative,omitempty"`
+	Accusative    string `json:"accusative,omitempty"`
+	Instrumental  string `json:"instrumental,omitempty"`
+	Prepositional string `json:"prepositional,omitempty"`
+}
+
+// PlayCounter счётчик прослушиваний персонального плейлиста (например, дней подряд).
+type PlayCounter struct {
+	Value       int    `json:"value"`
+	Description string `json:"description,omitempty"`
+	Updated     bool   `json:"updated,omitempty"`
 }
 
 // Generated playlist types (subset) mirroring C# YGeneratedPlaylistType enum.
@@ -28,23 +81,57 @@ const (
 	GeneratedPlaylistKinopoisk  = "Kinopoisk"    // Кинопоиск
 )
 
+// Visibility видимость плейлиста.
+type Visibility string
+
+const (
+	VisibilityPublic  Visibility = "public"
+	VisibilityPrivate Visibility = "private"
+)
+
+// Cover обложка плейлиста (пользовательская — Custom, иначе мозаика из обложек треков).
+type Cover struct {
+	Type     string   `json:"type,omitempty"` // "pic" или "mosaic"
+	URI      string   `json:"uri,omitempty"`
+	ItemsURI []string `json:"itemsUri,omitempty"`
+	Dir      string   `json:"dir,omitempty"`
+	Version  string   `json:"version,omitempty"`
+	Custom   bool     `json:"custom,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
 // Owner владелец плейлиста.
 type Owner struct {
-	Uid string `json:"uid"`
+	Uid      string `json:"uid"`
+	Login    string `json:"login,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Sex      string `json:"sex,omitempty"`
+	Verified bool   `json:"verified,omitempty"`
 }
 
 // TrackContainer связь трека и метаданных.
 type TrackContainer struct {
-	ID    string `json:"id,omitempty"`
-	Track *Track `json:"track,omitempty"`
+	ID            string     `json:"id,omitempty"`
+	AlbumID       string     `json:"albumId,omitempty"`
+	Track         *Track     `json:"track,omitempty"`
+	Timestamp     *time.Time `json:"timestamp,omitempty"`
+	OriginalIndex int        `json:"originalIndex,omitempty"`
+	Recent        bool       `json:"recent,omitempty"`
 }
 
-// Track упрощённая модель трека (часть полей).
+// Track трек плейлиста (основные поля для отображения).
 type Track struct {
-	ID      string        `json:"id"`
-	Title   string        `json:"title"`
-	Artists []TrackArtist `json:"artists,omitempty"`
-	Albums  []TrackAlbum  `json:"albums,omitempty"`
+	ID             string        `json:"id"`
+	RealID         string        `json:"realId,omitempty"`
+	Title          string        `json:"title"`
+	Version        string        `json:"version,omitempty"`
+	Artists        []TrackArtist `json:"artists,omitempty"`
+	Albums         []TrackAlbum  `json:"albums,omitempty"`
+	DurationMs     int64         `json:"durationMs,omitempty"`
+	CoverURI       string        `json:"coverUri,omitempty"`
+	ContentWarning string        `json:"contentWarning,omitempty"`
+	// Available false — трек изъят из каталога (nil — сервер не прислал поле).
+	Available *bool `json:"available,omitempty"`
 }
 
 type TrackArtist struct {
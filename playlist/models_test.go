@@ -0,0 +1,57 @@
+package playlist_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
+
+// фрагмент реального ответа users/{uid}/playlists/{kind}: числовые uid/kind/id
+const playlistJSON = `{
+	"owner": {"uid": 1130000000000001, "login": "editor", "name": "Редакция", "sex": "unknown", "verified": true},
+	"playlistUuid": "1f0e-uuid", "available": true, "uid": 1130000000000001, "kind": 1042,
+	"title": "Хиты недели", "description": "Лучшее", "revision": 17, "snapshot": 3, "trackCount": 1,
+	"visibility": "public", "collective": true, "backgroundColor": "#f2c1a0",
+	"created": "2024-01-05T10:00:00+00:00", "modified": "2024-06-01T08:30:00+00:00",
+	"cover": {"type": "pic", "dir": "/get-music-user-playlist/1/x", "version": "1", "uri": "avatars.yandex.net/x/%%", "custom": true},
+	"tags": [{"id": "5f2", "value": "chart"}],
+	"madeFor": {"userInfo": {"uid": 42, "login": "me"}, "caseForms": {"nominative": "Иван", "dative": "Ивану"}},
+	"playCounter": {"value": 3, "description": "3 дня подряд", "updated": true},
+	"similarPlaylists": [{"uid": 7, "kind": 3, "title": "Похожий"}],
+	"tracks": [{"id": 55, "albumId": 66, "timestamp": "2024-06-01T08:00:00+00:00", "originalIndex": 0, "recent": true,
+		"track": {"id": "55", "realId": 55, "title": "Песня", "durationMs": 201000, "available": true, "coverUri": "avatars/%%",
+			"artists": [{"id": 9, "name": "Исполнитель"}], "albums": [{"id": 66, "title": "Альбом"}]}}]
+}`
+
+func TestPlaylistFullModel(t *testing.T) {
+	var pl playlist.Playlist
+	if err := json.Unmarshal([]byte(playlistJSON), &pl); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if pl.Kind != "1042" || pl.Uid != "1130000000000001" || pl.Owner.Uid != "1130000000000001" || !pl.Owner.Verified {
+		t.Fatalf("ids/owner %+v %+v", pl, pl.Owner)
+	}
+	if pl.Visibility != playlist.VisibilityPublic || !pl.Collective || pl.Cover == nil || !pl.Cover.Custom || pl.Modified == nil {
+		t.Fatalf("metadata %+v", pl)
+	}
+	if len(pl.Tags) != 1 || pl.Tags[0].Value != "chart" || pl.MadeFor.UserInfo.Uid != "42" || pl.MadeFor.CaseForms.Dative != "Ивану" || pl.PlayCounter.Value != 3 {
+		t.Fatalf("tags/madeFor %+v", pl)
+	}
+	if len(pl.SimilarPlaylists) != 1 || pl.SimilarPlaylists[0].Kind != "3" {
+		t.Fatalf("similar %+v", pl.SimilarPlaylists)
+	}
+	tc := pl.Tracks[0]
+	if tc.ID != "55" || tc.AlbumID != "66" || !tc.Recent || tc.Track.RealID != "55" || tc.Track.DurationMs != 201000 || tc.Track.Artists[0].ID != "9" {
+		t.Fatalf("track %+v %+v", tc, tc.Track)
+	}
+	if k := tc.Key(); k.String() != "55:66" {
+		t.Fatalf("key %v", k)
+	}
+	// обратная сериализация остаётся строковой
+	data, _ := json.Marshal(pl)
+	var again playlist.Playlist
+	if err := json.Unmarshal(data, &again); err != nil || again.Kind != "1042" || again.Tracks[0].Track.Albums[0].ID != "66" {
+		t.Fatalf("round trip: %v %+v", err, again)
+	}
+}
@@ -0,0 +1,44 @@
+package playlistfmt
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type jspfDoc struct {
+	Playlist jspfPlaylist `json:"playlist"`
+}
+
+type jspfPlaylist struct {
+	Title      string      `json:"title,omitempty"`
+	Creator    string      `json:"creator,omitempty"`
+	Annotation string      `json:"annotation,omitempty"`
+	Track      []jspfTrack `json:"track"`
+}
+
+type jspfTrack struct {
+	Location   []string `json:"location,omitempty"`
+	Identifier []string `json:"identifier,omitempty"`
+	Title      string   `json:"title,omitempty"`
+	Creator    string   `json:"creator,omitempty"`
+	Album      string   `json:"album,omitempty"`
+	Duration   int64    `json:"duration,omitempty"`
+}
+
+// WriteJSPF пишет JSPF (JSON вариант XSPF).
+func WriteJSPF(w io.Writer, l *List) error {
+	doc := jspfDoc{Playlist: jspfPlaylist{Title: l.Title, Creator: l.Creator, Annotation: l.Annotation, Track: []jspfTrack{}}}
+	for _, e := range l.Entries {
+		doc.Playlist.Track = append(doc.Playlist.Track, jspfTrack{
+			Location:   nonEmpty(e.Location),
+			Identifier: nonEmpty(e.TrackURL()),
+			Title:      e.Title,
+			Creator:    e.Artist(),
+			Album:      e.Album,
+			Duration:   e.DurationMs,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
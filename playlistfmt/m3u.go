@@ -0,0 +1,44 @@
+package playlistfmt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// WriteM3U8 пишет extended M3U в UTF-8: #EXTINF с длительностью в секундах
+// и "Исполнитель - Название". Все записи должны иметь Location.
+func WriteM3U8(w io.Writer, l *List) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "#EXTM3U")
+	if l.Title != "" {
+		fmt.Fprintf(bw, "#PLAYLIST:%s\n", oneLine(l.Title))
+	}
+	for i, e := range l.Entries {
+		if e.Location == "" {
+			return fmt.Errorf("playlistfmt: entry %d (%s) has no location", i, e.ID)
+		}
+		secs := -1
+		if e.DurationMs > 0 {
+			secs = int((e.DurationMs + 500) / 1000)
+		}
+		title := e.Title
+		if a := e.Artist(); a != "" {
+			title = a + " - " + title
+		}
+		fmt.Fprintf(bw, "#EXTINF:%d,%s\n", secs, oneLine(title))
+		fmt.Fprintln(bw, oneLine(e.Location))
+	}
+	return bw.Flush()
+}
+
+// oneLine убирает переводы строк, ломающие построчный формат.
+func oneLine(s string) string {
+	b := []rune(s)
+	for i, r := range b {
+		if r == '\n' || r == '\r' {
+			b[i] = ' '
+		}
+	}
+	return string(b)
+}
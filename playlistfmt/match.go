@@ -0,0 +1,118 @@
+package playlistfmt
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultDurationTolerance допустимое расхождение длительностей, при котором записи
+// считаются одной и той же.
+const DefaultDurationTolerance = 3 * time.Second
+
+// Normalize приводит строку к виду для сравнения: нижний регистр, ё→е, без
+// уточнений в скобках ("(feat. …)", "[Remastered]"), без "feat."-хвоста и пунктуации.
+func Normalize(s string) string {
+	s = strings.ToLower(s)
+	s = strings.ReplaceAll(s, "ё", "е")
+	var b strings.Builder
+	depth := 0
+	for _, r := range s {
+		switch {
+		case r == '(' || r == '[':
+			depth++
+		case r == ')' || r == ']':
+			if depth > 0 {
+				depth--
+			}
+		case depth > 0:
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteByte(' ')
+		}
+	}
+	fields := strings.Fields(b.String())
+	for i, f := range fields {
+		if f == "feat" || f == "ft" || f == "featuring" {
+			fields = fields[:i]
+			break
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// Similarity коэффициент Дайса по биграммам нормализованных строк (0..1).
+func Similarity(a, b string) float64 {
+	a, b = Normalize(a), Normalize(b)
+	if a == b {
+		if a == "" {
+			return 0
+		}
+		return 1
+	}
+	ba, bb := bigrams(a), bigrams(b)
+	if len(ba) == 0 || len(bb) == 0 {
+		return 0
+	}
+	counts := make(map[string]int, len(ba))
+	for _, g := range ba {
+		counts[g]++
+	}
+	common := 0
+	for _, g := range bb {
+		if counts[g] > 0 {
+			counts[g]--
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(ba)+len(bb))
+}
+
+func bigrams(s string) []string {
+	r := []rune(s)
+	if len(r) < 2 {
+		return []string{s}
+	}
+	out := make([]string, 0, len(r)-1)
+	for i := 0; i+1 < len(r); i++ {
+		out = append(out, string(r[i:i+2]))
+	}
+	return out
+}
+
+// Score оценивает, насколько candidate совпадает с want (0..1): название (вес 0.5),
+// исполнители (0.35) и длительность (0.15). Неизвестные у одной из сторон исполнители
+// или длительность не учитываются, веса перераспределяются. Длительность в пределах
+// tolerance даёт полный балл и линейно падает до нуля при тройном расхождении.
+func Score(want, candidate Entry, tolerance time.Duration) float64 {
+	if tolerance <= 0 {
+		tolerance = DefaultDurationTolerance
+	}
+	sum, weight := 0.5*Similarity(want.Title, candidate.Title), 0.5
+	if len(want.Artists) > 0 && len(candidate.Artists) > 0 {
+		best := 0.0
+		for _, a := range want.Artists {
+			for _, c := range candidate.Artists {
+				best = max(best, Similarity(a, c))
+			}
+		}
+		// "A, B" против раздельного списка
+		best = max(best, Similarity(want.Artist(), candidate.Artist()))
+		sum += 0.35 * best
+		weight += 0.35
+	}
+	if want.DurationMs > 0 && candidate.DurationMs > 0 {
+		diff := time.Duration(want.DurationMs-candidate.DurationMs) * time.Millisecond
+		if diff < 0 {
+			diff = -diff
+		}
+		d := 1.0
+		if diff > tolerance {
+			d = max(0, 1-float64(diff-tolerance)/float64(2*tolerance))
+		}
+		sum += 0.15 * d
+		weight += 0.15
+	}
+	return sum / weight
+}
@@ -0,0 +1,186 @@
+// Package playlistfmt экспорт и импорт плейлистов в переносимых форматах
+// (M3U8, XSPF, JSPF; CSV — только импорт) для медиасерверов и плееров.
+package playlistfmt
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Banjirome/yandex-music-go/album"
+	"github.com/Banjirome/yandex-music-go/playlist"
+)
+
+// Format формат файла плейлиста.
+type Format string
+
+const (
+	M3U8 Format = "m3u8"
+	XSPF Format = "xspf"
+	JSPF Format = "jspf"
+)
+
+// Entry трек плейлиста.
+type Entry struct {
+	ID         string
+	AlbumID    string
+	Title      string
+	Artists    []string
+	Album      string
+	DurationMs int64
+	// ISRC код записи из импортируемого файла (если был).
+	ISRC string
+	// Location путь к локальному файлу или URL потока (см. Locate).
+	Location string
+}
+
+// Artist исполнители через запятую.
+func (e Entry) Artist() string { return strings.Join(e.Artists, ", ") }
+
+// TrackURL ссылка на трек на music.yandex.ru (идентификатор в XSPF/JSPF).
+func (e Entry) TrackURL() string {
+	if e.ID == "" {
+		return ""
+	}
+	if e.AlbumID == "" {
+		return "https://music.yandex.ru/track/" + e.ID
+	}
+	return "https://music.yandex.ru/album/" + e.AlbumID + "/track/" + e.ID
+}
+
+// List плейлист для экспорта.
+type List struct {
+	Title      string
+	Creator    string
+	Annotation string
+	Entries    []Entry
+}
+
+// Locator вычисляет Location записи.
+type Locator func(e Entry) string
+
+// Locate заполняет Location всех записей.
+func (l *List) Locate(loc Locator) {
+	for i := range l.Entries {
+		l.Entries[i].Location = loc(l.Entries[i])
+	}
+}
+
+// LocalFiles указывает на файлы в dir с именами как у Downloader.Batch.
+func LocalFiles(dir string) Locator {
+	return func(e Entry) string { return filepath.Join(dir, FileName(e)) }
+}
+
+// StreamURL подставляет в шаблон {id}, {albumId} и {key} ("id:albumId"),
+// например "http://nas:8080/stream/{key}".
+func StreamURL(template string) Locator {
+	return func(e Entry) string {
+		key := e.ID
+		if e.AlbumID != "" {
+			key += ":" + e.AlbumID
+		}
+		return strings.NewReplacer("{id}", e.ID, "{albumId}", e.AlbumID, "{key}", key).Replace(template)
+	}
+}
+
+// maxNameBytes ограничение длины имени файла (с запасом до 255 байт большинства ФС).
+const maxNameBytes = 200
+
+// FileName имя файла трека "Исполнители - Название [id].mp3" без символов, запрещённых
+// в путях Windows/Unix. id в имени разводит разные записи с одинаковыми исполнителем
+// и названием (ремастер, концертная версия). Используется Downloader.Batch и LocalFiles.
+func FileName(e Entry) string {
+	name := e.Title
+	if a := e.Artist(); a != "" {
+		name = a + " - " + name
+	}
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, " .")
+	for len(name) > maxNameBytes {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	switch {
+	case name == "":
+		name = e.ID
+	case e.ID != "":
+		name += " [" + e.ID + "]"
+	}
+	return name + ".mp3"
+}
+
+// Write пишет плейлист в указанном формате.
+func Write(w io.Writer, l *List, f Format) error {
+	switch f {
+	case M3U8:
+		return WriteM3U8(w, l)
+	case XSPF:
+		return WriteXSPF(w, l)
+	case JSPF:
+		return WriteJSPF(w, l)
+	}
+	return fmt.Errorf("playlistfmt: unknown format %q", f)
+}
+
+// FromPlaylist записи плейлиста (треки без метаданных получают только ID).
+func FromPlaylist(pl *playlist.Playlist) *List {
+	l := &List{Title: pl.Title, Annotation: pl.Description}
+	if pl.Owner != nil {
+		l.Creator = pl.Owner.Name
+		if l.Creator == "" {
+			l.Creator = pl.Owner.Login
+		}
+	}
+	for _, tc := range pl.Tracks {
+		k := tc.Key()
+		if k.Id == "" {
+			continue
+		}
+		e := Entry{ID: k.Id, AlbumID: k.AlbumId}
+		if t := tc.Track; t != nil {
+			e.Title, e.DurationMs = t.Title, t.DurationMs
+			for _, ar := range t.Artists {
+				e.Artists = append(e.Artists, ar.Name)
+			}
+			if len(t.Albums) > 0 {
+				e.Album = t.Albums[0].Title
+			}
+		}
+		l.Entries = append(l.Entries, e)
+	}
+	return l
+}
+
+// FromAlbum записи альбома, полученного с треками (Album.WithTracks), по томам.
+func FromAlbum(al *album.Album) *List {
+	l := &List{Title: al.Title}
+	var albumArtists []string
+	for _, ar := range al.Artists {
+		albumArtists = append(albumArtists, ar.Name)
+	}
+	l.Creator = strings.Join(albumArtists, ", ")
+	for _, vol := range al.Volumes {
+		for _, t := range vol {
+			e := Entry{ID: t.ID, AlbumID: al.ID, Title: t.Title, Album: al.Title, DurationMs: t.DurationMs}
+			for _, ar := range t.Artists {
+				if m, ok := ar.(map[string]any); ok {
+					if name, _ := m["name"].(string); name != "" {
+						e.Artists = append(e.Artists, name)
+					}
+				}
+			}
+			if len(e.Artists) == 0 {
+				e.Artists = albumArtists
+			}
+			l.Entries = append(l.Entries, e)
+		}
+	}
+	return l
+}
@@ -0,0 +1,110 @@
+package playlistfmt_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Banjirome/yandex-music-go/playlist"
+	"github.com/Banjirome/yandex-music-go/playlistfmt"
+)
+
+func sampleList() *playlistfmt.List {
+	return &playlistfmt.List{Title: "Дорога", Creator: "me", Entries: []playlistfmt.Entry{
+		{ID: "1", AlbumID: "10", Title: "Группа крови", Artists: []string{"Кино"}, Album: "Группа крови", DurationMs: 286600},
+		{ID: "2", Title: "AC/DC: live?", Artists: []string{"A", "B"}},
+	}}
+}
+
+func TestWriteM3U8(t *testing.T) {
+	l := sampleList()
+	l.Locate(playlistfmt.StreamURL("http://nas:8080/stream/{key}"))
+	var buf bytes.Buffer
+	if err := playlistfmt.WriteM3U8(&buf, l); err != nil {
+		t.Fatalf("m3u8: %v", err)
+	}
+	want := "#EXTM3U\n#PLAYLIST:Дорога\n" +
+		"#EXTINF:287,Кино - Группа крови\nhttp://nas:8080/stream/1:10\n" +
+		"#EXTINF:-1,A, B - AC/DC: live?\nhttp://nas:8080/stream/2\n"
+	if buf.String() != want {
+		t.Fatalf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+	if err := playlistfmt.WriteM3U8(&buf, sampleList()); err == nil {
+		t.Fatalf("entries without location must fail")
+	}
+}
+
+func TestLocalFilesNames(t *testing.T) {
+	l := sampleList()
+	l.Locate(playlistfmt.LocalFiles("music"))
+	if got := l.Entries[0].Location; got != filepath.Join("music", "Кино - Группа крови [1].mp3") {
+		t.Fatalf("location %q", got)
+	}
+	if got := playlistfmt.FileName(l.Entries[1]); got != "A, B - AC_DC_ live_ [2].mp3" {
+		t.Fatalf("sanitized %q", got)
+	}
+	live := l.Entries[0]
+	live.ID = "7"
+	if playlistfmt.FileName(live) == playlistfmt.FileName(l.Entries[0]) {
+		t.Fatalf("different recordings share a file name")
+	}
+	long := playlistfmt.Entry{Title: strings.Repeat("я", 300)}
+	if n := len(playlistfmt.FileName(long)); n > 210 {
+		t.Fatalf("name not truncated: %d bytes", n)
+	}
+}
+
+func TestWriteXSPFAndJSPF(t *testing.T) {
+	l := sampleList()
+	var buf bytes.Buffer
+	if err := playlistfmt.WriteXSPF(&buf, l); err != nil {
+		t.Fatalf("xspf: %v", err)
+	}
+	var x struct {
+		Title  string `xml:"title"`
+		Tracks []struct {
+			Identifier string `xml:"identifier"`
+			Creator    string `xml:"creator"`
+			Duration   int64  `xml:"duration"`
+		} `xml:"trackList>track"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &x); err != nil {
+		t.Fatalf("xspf parse: %v", err)
+	}
+	if x.Title != "Дорога" || len(x.Tracks) != 2 || x.Tracks[0].Identifier != "https://music.yandex.ru/album/10/track/1" || x.Tracks[0].Duration != 286600 || x.Tracks[1].Creator != "A, B" {
+		t.Fatalf("xspf %+v", x)
+	}
+
+	buf.Reset()
+	if err := playlistfmt.Write(&buf, l, playlistfmt.JSPF); err != nil {
+		t.Fatalf("jspf: %v", err)
+	}
+	var j struct {
+		Playlist struct {
+			Track []struct {
+				Identifier []string `json:"identifier"`
+				Title      string   `json:"title"`
+			} `json:"track"`
+		} `json:"playlist"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &j); err != nil {
+		t.Fatalf("jspf parse: %v", err)
+	}
+	if len(j.Playlist.Track) != 2 || j.Playlist.Track[1].Identifier[0] != "https://music.yandex.ru/track/2" {
+		t.Fatalf("jspf %+v", j)
+	}
+}
+
+func TestFromPlaylist(t *testing.T) {
+	pl := &playlist.Playlist{Title: "P", Owner: &playlist.Owner{Login: "bob"}, Tracks: []playlist.TrackContainer{
+		{ID: "1", Track: &playlist.Track{ID: "1", Title: "T", DurationMs: 1000, Artists: []playlist.TrackArtist{{Name: "X"}}, Albums: []playlist.TrackAlbum{{ID: "5", Title: "Al"}}}},
+		{ID: "2", AlbumID: "6"},
+	}}
+	l := playlistfmt.FromPlaylist(pl)
+	if l.Creator != "bob" || len(l.Entries) != 2 || l.Entries[0].Album != "Al" || l.Entries[0].AlbumID != "5" || l.Entries[1].AlbumID != "6" {
+		t.Fatalf("list %+v", l)
+	}
+}
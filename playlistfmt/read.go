@@ -0,0 +1,297 @@
+package playlistfmt
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CSV формат таблицы треков (только импорт): заголовок с колонками вроде
+// "Track Name", "Artist Name(s)", "Album Name", "Duration (ms)", "ISRC".
+const CSV Format = "csv"
+
+// DetectFormat определяет формат по расширению файла.
+func DetectFormat(name string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".m3u", ".m3u8":
+		return M3U8, nil
+	case ".xspf":
+		return XSPF, nil
+	case ".jspf", ".json":
+		return JSPF, nil
+	case ".csv":
+		return CSV, nil
+	}
+	return "", fmt.Errorf("playlistfmt: unknown playlist extension %q", filepath.Ext(name))
+}
+
+// Read читает плейлист в указанном формате. Ссылки на music.yandex.ru в location
+// или identifier заполняют ID/AlbumID.
+func Read(r io.Reader, f Format) (*List, error) {
+	switch f {
+	case M3U8:
+		return ReadM3U(r)
+	case XSPF:
+		return ReadXSPF(r)
+	case JSPF:
+		return ReadJSPF(r)
+	case CSV:
+		return ReadCSV(r)
+	}
+	return nil, fmt.Errorf("playlistfmt: unknown format %q", f)
+}
+
+// ReadM3U читает M3U/M3U8. Без #EXTINF исполнитель и название берутся из имени
+// файла вида "Исполнитель - Название.mp3".
+func ReadM3U(r io.Reader) (*List, error) {
+	l := &List{}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	var pending *Entry
+	for sc.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(sc.Text(), "\ufeff"))
+		switch {
+		case line == "" || line == "#EXTM3U":
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			l.Title = strings.TrimPrefix(line, "#PLAYLIST:")
+		case strings.HasPrefix(line, "#EXTINF:"):
+			info := strings.TrimPrefix(line, "#EXTINF:")
+			secs, title, _ := strings.Cut(info, ",")
+			// атрибуты вида -1 tvg-id="..." отбрасываем
+			secs, _, _ = strings.Cut(secs, " ")
+			e := splitArtistTitle(title)
+			if n, err := strconv.Atoi(secs); err == nil && n > 0 {
+				e.DurationMs = int64(n) * 1000
+			}
+			pending = &e
+		case strings.HasPrefix(line, "#"):
+		default:
+			e := Entry{}
+			if pending != nil {
+				e = *pending
+			} else {
+				e = fileNameEntry(strings.TrimSuffix(path.Base(filepath.ToSlash(line)), path.Ext(line)))
+			}
+			e.Location = line
+			if id, albumID := ParseTrackURL(line); id != "" {
+				e.ID, e.AlbumID = id, albumID
+			}
+			l.Entries = append(l.Entries, e)
+			pending = nil
+		}
+	}
+	return l, sc.Err()
+}
+
+// ReadXSPF читает XSPF.
+func ReadXSPF(r io.Reader) (*List, error) {
+	var doc xspfPlaylist
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("playlistfmt: xspf: %w", err)
+	}
+	l := &List{Title: doc.Title, Creator: doc.Creator, Annotation: doc.Annotation}
+	for _, t := range doc.Tracks {
+		l.Entries = append(l.Entries, spfEntry(t.Title, t.Creator, t.Album, t.Duration, t.Location, t.Identifier))
+	}
+	return l, nil
+}
+
+// ReadJSPF читает JSPF.
+func ReadJSPF(r io.Reader) (*List, error) {
+	var doc jspfDoc
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("playlistfmt: jspf: %w", err)
+	}
+	p := doc.Playlist
+	l := &List{Title: p.Title, Creator: p.Creator, Annotation: p.Annotation}
+	for _, t := range p.Track {
+		l.Entries = append(l.Entries, spfEntry(t.Title, t.Creator, t.Album, t.Duration, t.Location, t.Identifier))
+	}
+	return l, nil
+}
+
+func spfEntry(title, creator, album string, durationMs int64, locations, identifiers []string) Entry {
+	e := Entry{Title: title, Album: album, DurationMs: durationMs}
+	if creator != "" {
+		e.Artists = []string{creator}
+	}
+	if len(locations) > 0 {
+		e.Location = locations[0]
+	}
+	for _, ref := range append(append([]string(nil), identifiers...), locations...) {
+		if isrc, ok := strings.CutPrefix(strings.ToLower(ref), "isrc:"); ok && e.ISRC == "" {
+			e.ISRC = strings.ToUpper(isrc)
+			continue
+		}
+		if e.ID == "" {
+			e.ID, e.AlbumID = ParseTrackURL(ref)
+		}
+	}
+	return e
+}
+
+// csvColumns синонимы колонок CSV (в нижнем регистре).
+var csvColumns = map[string][]string{
+	"title":    {"title", "track", "track name", "track_name", "name", "song"},
+	"artist":   {"artist", "artists", "artist name(s)", "artist name", "artist_name", "creator"},
+	"album":    {"album", "album name", "album_name"},
+	"duration": {"duration_ms", "duration (ms)", "duration", "length"},
+	"isrc":     {"isrc"},
+	"id":       {"id", "yandex_id", "track_id", "url", "link"},
+}
+
+// ReadCSV читает таблицу с заголовком. Длительность понимается в мс (колонка с "ms"),
+// в секундах или как м:сс; несколько исполнителей разделяются запятой или ";".
+func ReadCSV(r io.Reader) (*List, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("playlistfmt: csv: %w", err)
+	}
+	col := map[string]int{}
+	durInMs := false
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		for key, names := range csvColumns {
+			for _, n := range names {
+				if _, seen := col[key]; !seen && h == n {
+					col[key] = i
+					if key == "duration" {
+						durInMs = strings.Contains(h, "ms")
+					}
+				}
+			}
+		}
+	}
+	if _, ok := col["title"]; !ok {
+		return nil, fmt.Errorf("playlistfmt: csv: no title column in header %v", header)
+	}
+	get := func(rec []string, key string) string {
+		if i, ok := col[key]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+	l := &List{}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("playlistfmt: csv: %w", err)
+		}
+		e := Entry{Title: get(rec, "title"), Album: get(rec, "album"), ISRC: strings.ToUpper(get(rec, "isrc"))}
+		for _, a := range strings.FieldsFunc(get(rec, "artist"), func(r rune) bool { return r == ',' || r == ';' }) {
+			if a = strings.TrimSpace(a); a != "" {
+				e.Artists = append(e.Artists, a)
+			}
+		}
+		e.DurationMs = parseDuration(get(rec, "duration"), durInMs)
+		if ref := get(rec, "id"); ref != "" {
+			if e.ID, e.AlbumID = ParseTrackURL(ref); e.ID == "" && isDigits(ref) {
+				e.ID = ref
+			}
+		}
+		if e.Title == "" && e.ID == "" {
+			continue
+		}
+		l.Entries = append(l.Entries, e)
+	}
+	return l, nil
+}
+
+func parseDuration(s string, inMs bool) int64 {
+	if s == "" {
+		return 0
+	}
+	if m, sec, ok := strings.Cut(s, ":"); ok {
+		mi, err1 := strconv.Atoi(m)
+		si, err2 := strconv.Atoi(sec)
+		if err1 != nil || err2 != nil {
+			return 0
+		}
+		return int64(mi*60+si) * 1000
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f <= 0 {
+		return 0
+	}
+	if inMs {
+		return int64(f)
+	}
+	return int64(f * 1000)
+}
+
+// ParseTrackURL извлекает id трека и альбома из ссылки music.yandex.ru
+// (/album/{albumId}/track/{id} или /track/{id}); для прочих строк возвращает пустые значения.
+func ParseTrackURL(s string) (id, albumID string) {
+	u, err := url.Parse(s)
+	if err != nil || !strings.Contains(u.Host, "music.yandex.") {
+		return "", ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		switch parts[i] {
+		case "album":
+			albumID = parts[i+1]
+		case "track":
+			id = parts[i+1]
+		}
+	}
+	if !isDigits(id) {
+		return "", ""
+	}
+	if !isDigits(albumID) {
+		albumID = ""
+	}
+	return id, albumID
+}
+
+// fileNameEntry разбирает имя файла без расширения; суффикс " [id]" (см. FileName)
+// становится ID.
+func fileNameEntry(name string) Entry {
+	var id string
+	if i := strings.LastIndex(name, " ["); i >= 0 && strings.HasSuffix(name, "]") && isDigits(name[i+2:len(name)-1]) {
+		name, id = name[:i], name[i+2:len(name)-1]
+	}
+	e := splitArtistTitle(name)
+	e.ID = id
+	return e
+}
+
+// splitArtistTitle делит "Исполнитель - Название"; без разделителя всё считается названием.
+func splitArtistTitle(s string) Entry {
+	s = strings.TrimSpace(s)
+	if artist, title, ok := strings.Cut(s, " - "); ok {
+		var artists []string
+		for _, a := range strings.Split(artist, ", ") {
+			if a = strings.TrimSpace(a); a != "" {
+				artists = append(artists, a)
+			}
+		}
+		return Entry{Title: strings.TrimSpace(title), Artists: artists}
+	}
+	return Entry{Title: s}
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package playlistfmt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Banjirome/yandex-music-go/playlistfmt"
+)
+
+func TestReadFormats(t *testing.T) {
+	cases := []struct {
+		name, file, src string
+	}{
+		{"m3u", "a.m3u8", "#EXTM3U\n#PLAYLIST:Road\n#EXTINF:286,Кино - Группа крови\n/music/1.mp3\n#EXTINF:-1,Intro\nhttps://music.yandex.ru/album/20/track/2\n"},
+		{"xspf", "a.xspf", `<?xml version="1.0"?><playlist version="1" xmlns="http://xspf.org/ns/0/"><title>Road</title><trackList>
+<track><location>/music/1.mp3</location><title>Группа крови</title><creator>Кино</creator><duration>286000</duration><identifier>isrc:ru1234</identifier></track>
+<track><title>Intro</title><identifier>https://music.yandex.ru/album/20/track/2</identifier></track></trackList></playlist>`},
+		{"jspf", "a.jspf", `{"playlist":{"title":"Road","track":[
+{"location":["/music/1.mp3"],"title":"Группа крови","creator":"Кино","duration":286000,"identifier":["isrc:RU1234"]},
+{"title":"Intro","identifier":["https://music.yandex.ru/album/20/track/2"]}]}}`},
+		{"csv", "a.csv", "\ufeffTrack Name,Artist Name(s),Album Name,Duration (ms),ISRC\nГруппа крови,Кино,Группа крови,286000,RU1234\nIntro,,,,\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := playlistfmt.DetectFormat(tc.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			l, err := playlistfmt.Read(strings.NewReader(tc.src), f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(l.Entries) != 2 {
+				t.Fatalf("entries %+v", l.Entries)
+			}
+			e := l.Entries[0]
+			if e.Title != "Группа крови" || e.Artist() != "Кино" || e.DurationMs/1000 != 286 {
+				t.Fatalf("entry %+v", e)
+			}
+			if tc.name != "m3u" && e.ISRC != "RU1234" {
+				t.Fatalf("isrc %q", e.ISRC)
+			}
+			if tc.name != "csv" && (l.Title != "Road" || l.Entries[1].ID != "2" || l.Entries[1].AlbumID != "20") {
+				t.Fatalf("list %+v", l)
+			}
+		})
+	}
+	if _, err := playlistfmt.DetectFormat("a.txt"); err == nil {
+		t.Fatal("expected unknown extension error")
+	}
+}
+
+func TestReadM3UFileNames(t *testing.T) {
+	l, err := playlistfmt.ReadM3U(strings.NewReader("C:\\Music\\Ария, Кипелов - Штиль [55].mp3\nbare.flac\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := l.Entries[0]; e.Title != "Штиль" || len(e.Artists) != 2 || e.Artists[1] != "Кипелов" || e.ID != "55" {
+		t.Fatalf("entry %+v", e)
+	}
+	if e := l.Entries[1]; e.Title != "bare" || e.Artists != nil {
+		t.Fatalf("entry %+v", e)
+	}
+}
+
+func TestScore(t *testing.T) {
+	want := playlistfmt.Entry{Title: "Uprising", Artists: []string{"MUSE"}, DurationMs: 305000}
+	same := playlistfmt.Entry{Title: "Uprising (Remastered)", Artists: []string{"Muse"}, DurationMs: 304000}
+	if s := playlistfmt.Score(want, same, 0); s != 1 {
+		t.Fatalf("same score %v", s)
+	}
+	longer := same
+	longer.DurationMs = 400000
+	if s := playlistfmt.Score(want, longer, 0); s >= 0.9 || s < 0.8 {
+		t.Fatalf("duration penalty score %v", s)
+	}
+	other := playlistfmt.Entry{Title: "Hysteria", Artists: []string{"Muse"}, DurationMs: 305000}
+	if s := playlistfmt.Score(want, other, 0); s > 0.6 {
+		t.Fatalf("other score %v", s)
+	}
+	if n := playlistfmt.Normalize("Ёлка feat. Кто-то (Live) [2020]!"); n != "елка" {
+		t.Fatalf("normalize %q", n)
+	}
+}
@@ -0,0 +1,57 @@
+package playlistfmt
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type xspfPlaylist struct {
+	XMLName    xml.Name    `xml:"http://xspf.org/ns/0/ playlist"`
+	Version    string      `xml:"version,attr"`
+	Title      string      `xml:"title,omitempty"`
+	Creator    string      `xml:"creator,omitempty"`
+	Annotation string      `xml:"annotation,omitempty"`
+	Tracks     []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location   []string `xml:"location,omitempty"`
+	Identifier []string `xml:"identifier,omitempty"`
+	Title      string   `xml:"title,omitempty"`
+	Creator    string   `xml:"creator,omitempty"`
+	Album      string   `xml:"album,omitempty"`
+	Duration   int64    `xml:"duration,omitempty"` // мс
+}
+
+// WriteXSPF пишет XSPF (XML Shareable Playlist Format); identifier — ссылка на трек
+// на music.yandex.ru, по ней импорт находит трек без поиска.
+func WriteXSPF(w io.Writer, l *List) error {
+	out := xspfPlaylist{Version: "1", Title: l.Title, Creator: l.Creator, Annotation: l.Annotation}
+	for _, e := range l.Entries {
+		out.Tracks = append(out.Tracks, xspfTrack{
+			Location:   nonEmpty(e.Location),
+			Identifier: nonEmpty(e.TrackURL()),
+			Title:      e.Title,
+			Creator:    e.Artist(),
+			Album:      e.Album,
+			Duration:   e.DurationMs,
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func nonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
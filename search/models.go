@@ -55,11 +55,15 @@ type SearchPlaylist struct {
 }
 
 type SearchTrack struct {
-	ID       string          `json:"id"`
-	Title    string          `json:"title"`
-	Artists  []TrackArtist   `json:"artists,omitempty"`
-	Albums   []TrackAlbumRef `json:"albums,omitempty"`
-	Explicit bool            `json:"explicit"`
+	ID         string          `json:"id"`
+	Title      string          `json:"title"`
+	Version    string          `json:"version,omitempty"`
+	DurationMs int64           `json:"durationMs,omitempty"`
+	Artists    []TrackArtist   `json:"artists,omitempty"`
+	Albums     []TrackAlbumRef `json:"albums,omitempty"`
+	Explicit   bool            `json:"explicit"`
+	// Available false — трек изъят из каталога.
+	Available *bool `json:"available,omitempty"`
 }
 
 type TrackArtist struct {
@@ -0,0 +1,534 @@
+// Package ynison содержит in-process websocket сервер, эмулирующий Ynison
+// (redirector -> state), для детерминированных тестов client.Player.
+package ynison
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	yn "github.com/Banjirome/yandex-music-go/ynison"
+	"nhooyr.io/websocket"
+)
+
+const (
+	// RedirectPath путь websocket редиректора.
+	RedirectPath = "/redirector.YnisonRedirectService/GetRedirectToYnison"
+	// StatePath путь state websocket.
+	StatePath = "/ynison_state.YnisonStateService/PutYnisonState"
+)
+
+// Option настраивает Server.
+type Option func(*Server)
+
+// WithTokens ограничивает набор принимаемых OAuth токенов (по умолчанию принимается любой непустой).
+func WithTokens(tokens ...string) Option {
+	return func(s *Server) {
+		for _, t := range tokens {
+			s.tokens[t] = true
+		}
+	}
+}
+
+// WithKeepAlive задаёт keep_alive_params, отдаваемые редиректором.
+func WithKeepAlive(interval, timeout time.Duration) Option {
+	return func(s *Server) {
+		s.keepAlive = &yn.KeepAliveParams{
+			KeepAliveTimeSeconds:    int(interval / time.Second),
+			KeepAliveTimeoutSeconds: int(timeout / time.Second),
+		}
+	}
+}
+
+// WithPlayerState задаёт начальное состояние плеера.
+func WithPlayerState(ps *yn.PlayerState) Option {
+	return func(s *Server) { s.playerState = ps }
+}
+
+// Server эмулирует протокол Ynison: выдачу redirect ticket, bootstrap
+// update_full_state, правила версий и рассылку состояния всем устройствам.
+type Server struct {
+	srv *httptest.Server
+
+	tokens    map[string]bool
+	keepAlive *yn.KeepAliveParams
+
+	mu          sync.Mutex
+	tickets     map[string]string // ticket -> device id
+	devices     map[string]*device
+	order       []string
+	playerState *yn.PlayerState
+	pings       int
+	updates     []Update
+}
+
+// Update фиксирует принятое или отклонённое сервером сообщение устройства.
+type Update struct {
+	DeviceID string
+	Kind     string
+	Accepted bool
+}
+
+type device struct {
+	id   string
+	info *yn.Device
+	conn *websocket.Conn
+}
+
+// NewServer запускает сервер на локальном порту. Закрывается через Close.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		tokens:  map[string]bool{},
+		tickets: map[string]string{},
+		devices: map[string]*device{},
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	if s.playerState == nil {
+		ver := &yn.Version{DeviceID: "ymtest", Version: "0", TimestampMs: 0}
+		s.playerState = &yn.PlayerState{
+			PlayerQueue: &yn.PlayerQueue{CurrentPlayableIndex: -1, Version: ver},
+			Status:      &yn.PlayerStateStatus{Paused: true, PlaybackSpeed: 1, Version: ver},
+		}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(RedirectPath, s.handleRedirect)
+	mux.HandleFunc(StatePath, s.handleState)
+	s.srv = httptest.NewServer(mux)
+	return s
+}
+
+// Host адрес сервера (host:port), который редиректор отдаёт в поле host.
+func (s *Server) Host() string { return strings.TrimPrefix(s.srv.URL, "http://") }
+
+// RedirectURL адрес редиректора для client.WithYnisonURLs.
+func (s *Server) RedirectURL() string { return "ws://" + s.Host() + RedirectPath }
+
+// StateURL шаблон адреса state websocket для client.WithYnisonURLs.
+func (s *Server) StateURL() string { return "ws://{host}" + StatePath }
+
+// Close закрывает все соединения и останавливает сервер.
+func (s *Server) Close() {
+	s.mu.Lock()
+	for _, d := range s.devices {
+		_ = d.conn.CloseNow()
+	}
+	s.mu.Unlock()
+	s.srv.CloseClientConnections()
+	s.srv.Close()
+}
+
+// Devices возвращает id подключённых устройств в порядке подключения.
+func (s *Server) Devices() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.order...)
+}
+
+// Pings возвращает количество websocket ping, полученных state соединениями.
+func (s *Server) Pings() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pings
+}
+
+// Updates возвращает журнал обработанных сообщений устройств.
+func (s *Server) Updates() []Update {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Update(nil), s.updates...)
+}
+
+// PlayerState возвращает копию текущего состояния плеера на сервере.
+func (s *Server) PlayerState() *yn.PlayerState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp := *s.playerState
+	return &cp
+}
+
+// SetPlayerState применяет состояние от имени "другого" устройства (без проверки версий)
+// и рассылает его всем подключённым устройствам.
+func (s *Server) SetPlayerState(ctx context.Context, ps *yn.PlayerState) error {
+	s.mu.Lock()
+	s.playerState = ps
+	s.mu.Unlock()
+	return s.broadcast(ctx)
+}
+
+// SendError отправляет устройству сообщение об ошибке Ynison.
+func (s *Server) SendError(ctx context.Context, deviceID string, e *yn.Error) error {
+	s.mu.Lock()
+	d := s.devices[deviceID]
+	s.mu.Unlock()
+	if d == nil {
+		return errors.New("ynison: unknown device " + deviceID)
+	}
+	b, _ := json.Marshal(yn.ErrorMessage{Error: e})
+	return d.conn.Write(ctx, websocket.MessageText, b)
+}
+
+// Disconnect закрывает state соединение устройства со стороны сервера.
+func (s *Server) Disconnect(deviceID string) {
+	s.mu.Lock()
+	d := s.devices[deviceID]
+	s.mu.Unlock()
+	if d != nil {
+		_ = d.conn.Close(websocket.StatusGoingAway, "disconnected by server")
+	}
+}
+
+func (s *Server) handleRedirect(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	meta, err := parseProtocol(r.Header.Get("Sec-WebSocket-Protocol"))
+	if err != nil || meta.DeviceID == "" {
+		http.Error(w, "bad protocol header", http.StatusBadRequest)
+		return
+	}
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{InsecureSkipVerify: true})
+	if err != nil {
+		return
+	}
+	defer conn.CloseNow()
+	ticket := randomHex()
+	s.mu.Lock()
+	s.tickets[ticket] = meta.DeviceID
+	s.mu.Unlock()
+	red := yn.Redirect{Host: s.Host(), RedirectTicket: ticket, SessionID: randomHex(), KeepAlive: s.keepAlive}
+	b, _ := json.Marshal(red)
+	if err := conn.Write(r.Context(), websocket.MessageText, b); err != nil {
+		return
+	}
+	// клиент держит соединение открытым до Close; просто ждём закрытия
+	for {
+		if _, _, err := conn.Read(r.Context()); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	meta, err := parseProtocol(r.Header.Get("Sec-WebSocket-Protocol"))
+	if err != nil {
+		http.Error(w, "bad protocol header", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	owner, ok := s.tickets[meta.RedirectTicket]
+	delete(s.tickets, meta.RedirectTicket)
+	s.mu.Unlock()
+	if !ok || owner != meta.DeviceID {
+		http.Error(w, "invalid redirect ticket", http.StatusForbidden)
+		return
+	}
+	conn, err := websocket.Accept(&pingCountingWriter{ResponseWriter: w, onPing: s.countPing}, r, &websocket.AcceptOptions{InsecureSkipVerify: true})
+	if err != nil {
+		return
+	}
+	defer conn.CloseNow()
+	ctx := r.Context()
+	d := &device{id: meta.DeviceID, conn: conn}
+	defer s.remove(ctx, d)
+	for {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+		s.handleMessage(ctx, d, data)
+	}
+}
+
+// inbound объединение поддерживаемых команд устройства.
+type inbound struct {
+	UpdateFullState *struct {
+		PlayerState *yn.PlayerState `json:"player_state"`
+		Device      *yn.Device      `json:"device"`
+	} `json:"update_full_state"`
+	UpdatePlayerState   *yn.PlayerState `json:"update_player_state"`
+	UpdatePlayingStatus *struct {
+		PlayingStatus *yn.PlayerStateStatus `json:"playing_status"`
+	} `json:"update_playing_status"`
+}
+
+func (s *Server) handleMessage(ctx context.Context, d *device, data []byte) {
+	var msg inbound
+	if err := json.Unmarshal(data, &msg); err != nil {
+		_ = s.sendTo(ctx, d, &yn.Error{HttpCode: http.StatusBadRequest, Message: "malformed message"})
+		return
+	}
+	switch {
+	case msg.UpdateFullState != nil:
+		s.mu.Lock()
+		if _, joined := s.devices[d.id]; !joined {
+			s.order = append(s.order, d.id)
+		}
+		d.info = msg.UpdateFullState.Device
+		s.devices[d.id] = d
+		// shadow-устройство только регистрируется и не перетирает состояние
+		accepted := d.info == nil || !d.info.IsShadow
+		if accepted {
+			accepted = s.applyLocked(msg.UpdateFullState.PlayerState)
+		}
+		s.updates = append(s.updates, Update{DeviceID: d.id, Kind: "update_full_state", Accepted: accepted})
+		s.mu.Unlock()
+		_ = s.broadcast(ctx)
+	case msg.UpdatePlayerState != nil:
+		s.mu.Lock()
+		accepted := s.applyLocked(msg.UpdatePlayerState)
+		s.updates = append(s.updates, Update{DeviceID: d.id, Kind: "update_player_state", Accepted: accepted})
+		s.mu.Unlock()
+		s.afterUpdate(ctx, d, accepted)
+	case msg.UpdatePlayingStatus != nil && msg.UpdatePlayingStatus.PlayingStatus != nil:
+		s.mu.Lock()
+		accepted := s.applyLocked(&yn.PlayerState{Status: msg.UpdatePlayingStatus.PlayingStatus})
+		s.updates = append(s.updates, Update{DeviceID: d.id, Kind: "update_playing_status", Accepted: accepted})
+		s.mu.Unlock()
+		s.afterUpdate(ctx, d, accepted)
+	default:
+		_ = s.sendTo(ctx, d, &yn.Error{HttpCode: http.StatusBadRequest, Message: "unsupported message"})
+	}
+}
+
+// afterUpdate рассылает принятые изменения всем, а отклонённые — возвращает
+// отправителю актуальное состояние для синхронизации.
+func (s *Server) afterUpdate(ctx context.Context, d *device, accepted bool) {
+	if accepted {
+		_ = s.broadcast(ctx)
+		return
+	}
+	_ = s.sendState(ctx, d)
+}
+
+// applyLocked применяет части состояния, версии которых не старее текущих.
+// Обновление без версии или с более ранним timestamp считается конфликтом.
+func (s *Server) applyLocked(ps *yn.PlayerState) bool {
+	if ps == nil {
+		return false
+	}
+	if ps.PlayerQueue != nil && !newer(ps.PlayerQueue.Version, s.playerState.PlayerQueue.Version) {
+		return false
+	}
+	if ps.Status != nil && !newer(ps.Status.Version, s.playerState.Status.Version) {
+		return false
+	}
+	if ps.PlayerQueue == nil && ps.Status == nil {
+		return false
+	}
+	next := *s.playerState
+	if ps.PlayerQueue != nil {
+		next.PlayerQueue = ps.PlayerQueue
+	}
+	if ps.Status != nil {
+		next.Status = ps.Status
+	}
+	s.playerState = &next
+	return true
+}
+
+func newer(in, cur *yn.Version) bool {
+	if in == nil {
+		return false
+	}
+	if cur == nil {
+		return true
+	}
+	return in.TimestampMs >= cur.TimestampMs
+}
+
+func (s *Server) stateLocked() yn.State {
+	st := yn.State{PlayerState: s.playerState, TimestampMs: time.Now().UnixMilli()}
+	for _, id := range s.order {
+		d := s.devices[id]
+		full := yn.DeviceFull{Session: &yn.Session{ID: id}}
+		if d.info != nil {
+			full.Device = *d.info
+		}
+		st.Devices = append(st.Devices, full)
+	}
+	return st
+}
+
+func (s *Server) broadcast(ctx context.Context) error {
+	s.mu.Lock()
+	b, _ := json.Marshal(s.stateLocked())
+	conns := make([]*websocket.Conn, 0, len(s.order))
+	for _, id := range s.order {
+		conns = append(conns, s.devices[id].conn)
+	}
+	s.mu.Unlock()
+	var firstErr error
+	for _, c := range conns {
+		if err := c.Write(ctx, websocket.MessageText, b); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (s *Server) sendState(ctx context.Context, d *device) error {
+	s.mu.Lock()
+	b, _ := json.Marshal(s.stateLocked())
+	s.mu.Unlock()
+	return d.conn.Write(ctx, websocket.MessageText, b)
+}
+
+func (s *Server) sendTo(ctx context.Context, d *device, e *yn.Error) error {
+	b, _ := json.Marshal(yn.ErrorMessage{Error: e})
+	return d.conn.Write(ctx, websocket.MessageText, b)
+}
+
+func (s *Server) remove(ctx context.Context, d *device) {
+	s.mu.Lock()
+	if s.devices[d.id] != d {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.devices, d.id)
+	for i, id := range s.order {
+		if id == d.id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	s.mu.Unlock()
+	_ = s.broadcast(context.WithoutCancel(ctx))
+}
+
+func (s *Server) countPing() {
+	s.mu.Lock()
+	s.pings++
+	s.mu.Unlock()
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	tok, ok := strings.CutPrefix(r.Header.Get("Authorization"), "OAuth ")
+	if !ok || tok == "" {
+		return false
+	}
+	return len(s.tokens) == 0 || s.tokens[tok]
+}
+
+// protocolMeta JSON из заголовка Sec-WebSocket-Protocol ("Bearer, v2, {...}").
+type protocolMeta struct {
+	DeviceID       string          `json:"Ynison-Device-Id"`
+	DeviceInfo     json.RawMessage `json:"Ynison-Device-Info"`
+	RedirectTicket string          `json:"Ynison-Redirect-Ticket"`
+}
+
+func parseProtocol(h string) (*protocolMeta, error) {
+	i := strings.Index(h, "{")
+	if i < 0 {
+		return nil, errors.New("ynison: protocol meta missing")
+	}
+	var m protocolMeta
+	if err := json.Unmarshal([]byte(h[i:]), &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func randomHex() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// pingCountingWriter подменяет соединение при Hijack, чтобы считать входящие ping фреймы
+// (nhooyr.io/websocket отвечает на них внутри Read и не даёт hook'а).
+type pingCountingWriter struct {
+	http.ResponseWriter
+	onPing func()
+}
+
+func (w *pingCountingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := w.ResponseWriter.(http.Hijacker).Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &frameSniffer{Conn: conn, onPing: w.onPing}, brw, nil
+}
+
+// frameSniffer разбирает заголовки входящих websocket фреймов, не изменяя поток.
+type frameSniffer struct {
+	net.Conn
+	onPing func()
+	hdr    []byte
+	skip   int64
+}
+
+func (c *frameSniffer) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.scan(p[:n])
+	return n, err
+}
+
+func (c *frameSniffer) scan(b []byte) {
+	for len(b) > 0 {
+		if c.skip > 0 {
+			k := min(int64(len(b)), c.skip)
+			c.skip -= k
+			b = b[k:]
+			continue
+		}
+		c.hdr = append(c.hdr, b[0])
+		b = b[1:]
+		need := headerLen(c.hdr)
+		if need < 0 || len(c.hdr) < need {
+			continue
+		}
+		if c.hdr[0]&0x0f == 0x9 {
+			c.onPing()
+		}
+		c.skip = payloadLen(c.hdr)
+		c.hdr = c.hdr[:0]
+	}
+}
+
+func headerLen(h []byte) int {
+	if len(h) < 2 {
+		return -1
+	}
+	n := 2
+	switch h[1] & 0x7f {
+	case 126:
+		n += 2
+	case 127:
+		n += 8
+	}
+	if h[1]&0x80 != 0 {
+		n += 4
+	}
+	return n
+}
+
+func payloadLen(h []byte) int64 {
+	switch l := h[1] & 0x7f; l {
+	case 126:
+		return int64(h[2])<<8 | int64(h[3])
+	case 127:
+		var n int64
+		for _, b := range h[2:10] {
+			n = n<<8 | int64(b)
+		}
+		return n
+	default:
+		return int64(l)
+	}
+}
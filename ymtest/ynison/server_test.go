@@ -0,0 +1,120 @@
+package ynison_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	fake "github.com/Banjirome/yandex-music-go/ymtest/ynison"
+	"github.com/Banjirome/yandex-music-go/ynison"
+	"nhooyr.io/websocket"
+)
+
+func header(deviceID, ticket string) http.Header {
+	h := http.Header{}
+	h.Set("Authorization", "OAuth tok")
+	h.Set("Sec-WebSocket-Protocol", fmt.Sprintf(`Bearer, v2, {"Ynison-Device-Id":"%s","Ynison-Redirect-Ticket":"%s"}`, deviceID, ticket))
+	return h
+}
+
+// dialState проходит redirect -> state и отправляет bootstrap не-shadow устройства.
+func dialState(t *testing.T, ctx context.Context, srv *fake.Server, deviceID string) *websocket.Conn {
+	t.Helper()
+	rc, _, err := websocket.Dial(ctx, srv.RedirectURL(), &websocket.DialOptions{HTTPHeader: header(deviceID, "")})
+	if err != nil {
+		t.Fatalf("redirect dial: %v", err)
+	}
+	t.Cleanup(func() { rc.CloseNow() })
+	_, data, err := rc.Read(ctx)
+	if err != nil {
+		t.Fatalf("redirect read: %v", err)
+	}
+	var red ynison.Redirect
+	if err := json.Unmarshal(data, &red); err != nil {
+		t.Fatalf("redirect decode: %v", err)
+	}
+	sc, _, err := websocket.Dial(ctx, strings.Replace(srv.StateURL(), "{host}", red.Host, 1), &websocket.DialOptions{HTTPHeader: header(deviceID, red.RedirectTicket)})
+	if err != nil {
+		t.Fatalf("state dial: %v", err)
+	}
+	t.Cleanup(func() { sc.CloseNow() })
+	write(t, ctx, sc, map[string]any{"update_full_state": map[string]any{
+		"device": map[string]any{"info": map[string]any{"device_id": deviceID}},
+	}})
+	return sc
+}
+
+func write(t *testing.T, ctx context.Context, c *websocket.Conn, v any) {
+	t.Helper()
+	b, _ := json.Marshal(v)
+	if err := c.Write(ctx, websocket.MessageText, b); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func read(t *testing.T, ctx context.Context, c *websocket.Conn) ynison.State {
+	t.Helper()
+	_, data, err := c.Read(ctx)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var st ynison.State
+	if err := json.Unmarshal(data, &st); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return st
+}
+
+func TestStateTicketRequired(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+	_, resp, err := websocket.Dial(ctx, strings.Replace(srv.StateURL(), "{host}", srv.Host(), 1), &websocket.DialOptions{HTTPHeader: header("d", "forged")})
+	if err == nil || resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 for forged ticket, err=%v", err)
+	}
+}
+
+func TestVersionConflict(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	a := dialState(t, ctx, srv, "a")
+	read(t, ctx, a) // bootstrap broadcast
+	b := dialState(t, ctx, srv, "b")
+	read(t, ctx, b)
+	if st := read(t, ctx, a); len(st.Devices) != 2 {
+		t.Fatalf("expected fan-out with 2 devices got %d", len(st.Devices))
+	}
+
+	now := time.Now().UnixMilli()
+	fresh := &ynison.Version{DeviceID: "a", Version: "1", TimestampMs: now}
+	write(t, ctx, a, map[string]any{"update_player_state": ynison.PlayerState{
+		PlayerQueue: &ynison.PlayerQueue{PlayableList: []ynison.PlayableItem{{PlayableID: "1"}}, Version: fresh},
+	}})
+	for _, c := range []*websocket.Conn{a, b} {
+		if st := read(t, ctx, c); len(st.PlayerState.PlayerQueue.PlayableList) != 1 {
+			t.Fatalf("accepted update not broadcast: %+v", st.PlayerState.PlayerQueue)
+		}
+	}
+
+	stale := &ynison.Version{DeviceID: "b", Version: "2", TimestampMs: now - 1000}
+	write(t, ctx, b, map[string]any{"update_player_state": ynison.PlayerState{
+		PlayerQueue: &ynison.PlayerQueue{Version: stale},
+	}})
+	// отправитель получает актуальное состояние, а не своё
+	if st := read(t, ctx, b); st.PlayerState.PlayerQueue.Version.Version != "1" {
+		t.Fatalf("stale update applied: %+v", st.PlayerState.PlayerQueue.Version)
+	}
+	ups := srv.Updates()
+	last := ups[len(ups)-1]
+	if last.DeviceID != "b" || last.Accepted {
+		t.Fatalf("expected rejected update from b got %+v", last)
+	}
+}